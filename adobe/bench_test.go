@@ -0,0 +1,76 @@
+package adobe
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+var benchDump = func() string {
+	var b strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&b, "%d-|--|-user%d@example.com-|-EQ2nCsm4N5bioxG6CatHBw==-|-my hint %d|--\n", i, i, i)
+	}
+	b.WriteString("10000 rows selected.\n")
+	return b.String()
+}()
+
+func BenchmarkRead(b *testing.B) {
+	b.SetBytes(int64(len(benchDump)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cr := NewCredReader(strings.NewReader(benchDump))
+		for {
+			record, err := cr.Read()
+			if err != nil {
+				break
+			}
+			_, _ = ParseRecord(record)
+		}
+	}
+}
+
+func BenchmarkReadInto(b *testing.B) {
+	b.SetBytes(int64(len(benchDump)))
+	b.ReportAllocs()
+	var c Cred
+	for i := 0; i < b.N; i++ {
+		cr := NewCredReader(strings.NewReader(benchDump))
+		for {
+			err := cr.ReadInto(&c)
+			if err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkReadBytes(b *testing.B) {
+	b.SetBytes(int64(len(benchDump)))
+	b.ReportAllocs()
+	var c CredBytes
+	for i := 0; i < b.N; i++ {
+		cr := NewCredReader(strings.NewReader(benchDump))
+		for {
+			err := cr.ReadBytes(&c)
+			if err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkParallelRead(b *testing.B) {
+	b.SetBytes(int64(len(benchDump)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pr := NewParallelCredReader(strings.NewReader(benchDump), &ParallelOptions{ChunkSize: 64 << 10})
+		for {
+			if _, err := pr.Read(); err == io.EOF {
+				break
+			}
+		}
+		pr.Close()
+	}
+}
@@ -0,0 +1,117 @@
+package adobe
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// BlockCounter tallies encrypted password blocks. Since the passwords
+// were encrypted in ECB mode, identical 8-byte plaintext blocks produce
+// identical cipher text blocks, so frequent blocks correspond to common
+// password fragments.
+type BlockCounter struct {
+	blocks    map[uint64]*BlockCount
+	passwords map[string]int
+}
+
+// BlockCount is the number of occurrences of a cipher text block.
+type BlockCount struct {
+	Block uint64
+	Count int // occurrences at any position
+	First int // occurrences as the first block of a password
+}
+
+// Later returns the number of occurrences after the first block.
+func (b *BlockCount) Later() int {
+	return b.Count - b.First
+}
+
+// PasswordCount is the number of occurrences of a full encrypted
+// password.
+type PasswordCount struct {
+	Password []byte
+	Count    int
+}
+
+// NewBlockCounter constructs a BlockCounter.
+func NewBlockCounter() *BlockCounter {
+	return &BlockCounter{make(map[uint64]*BlockCount), make(map[string]int)}
+}
+
+// Add tallies the blocks and password of a credential.
+func (bc *BlockCounter) Add(c *Cred) {
+	if len(c.Password) == 0 {
+		return
+	}
+	bc.passwords[string(c.Password)]++
+	for i, block := range c.Blocks() {
+		b, ok := bc.blocks[block]
+		if !ok {
+			b = &BlockCount{Block: block}
+			bc.blocks[block] = b
+		}
+		b.Count++
+		if i == 0 {
+			b.First++
+		}
+	}
+}
+
+// Blocks returns the block counts ranked from most to least frequent.
+func (bc *BlockCounter) Blocks() []BlockCount {
+	blocks := make([]BlockCount, 0, len(bc.blocks))
+	for _, b := range bc.blocks {
+		blocks = append(blocks, *b)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].Count != blocks[j].Count {
+			return blocks[i].Count > blocks[j].Count
+		}
+		return blocks[i].Block < blocks[j].Block
+	})
+	return blocks
+}
+
+// Passwords returns the password counts ranked from most to least
+// frequent.
+func (bc *BlockCounter) Passwords() []PasswordCount {
+	passwords := make([]PasswordCount, 0, len(bc.passwords))
+	for p, n := range bc.passwords {
+		passwords = append(passwords, PasswordCount{[]byte(p), n})
+	}
+	sort.Slice(passwords, func(i, j int) bool {
+		if passwords[i].Count != passwords[j].Count {
+			return passwords[i].Count > passwords[j].Count
+		}
+		return string(passwords[i].Password) < string(passwords[j].Password)
+	})
+	return passwords
+}
+
+// WriteBlockCounts writes block counts as tab-separated lines of
+// count, first, later, and hex block, which can be re-sorted with
+// sort(1) on any column.
+func WriteBlockCounts(w io.Writer, blocks []BlockCount) error {
+	bw := bufio.NewWriter(w)
+	for _, b := range blocks {
+		if _, err := fmt.Fprintf(bw, "%d\t%d\t%d\t%016x\n", b.Count, b.First, b.Later(), b.Block); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WritePasswordCounts writes password counts as tab-separated lines of
+// count and base64 password, as encoded in the dump.
+func WritePasswordCounts(w io.Writer, passwords []PasswordCount) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range passwords {
+		if _, err := fmt.Fprintf(bw, "%d\t%s\n", p.Count, base64.StdEncoding.EncodeToString(p.Password)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,46 @@
+package adobe
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockCounter(t *testing.T) {
+	a := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	b := []byte{9, 10, 11, 12, 13, 14, 15, 16}
+	bc := NewBlockCounter()
+	for _, pw := range [][]byte{a, a, concat(a, b), concat(b, a), nil} {
+		bc.Add(&Cred{Password: pw})
+	}
+
+	wantBlocks := []BlockCount{
+		{0x0102030405060708, 4, 3},
+		{0x090a0b0c0d0e0f10, 2, 1},
+	}
+	blocks := bc.Blocks()
+	if len(blocks) != len(wantBlocks) {
+		t.Fatalf("blocks: %v want %v", blocks, wantBlocks)
+	}
+	for i := range blocks {
+		if blocks[i] != wantBlocks[i] {
+			t.Errorf("block #%d: %v want %v", i, blocks[i], wantBlocks[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBlockCounts(&buf, blocks); err != nil {
+		t.Fatal(err)
+	}
+	if want := "4\t3\t1\t0102030405060708\n2\t1\t1\t090a0b0c0d0e0f10\n"; buf.String() != want {
+		t.Errorf("WriteBlockCounts: %q want %q", buf.String(), want)
+	}
+
+	passwords := bc.Passwords()
+	if len(passwords) != 3 || passwords[0].Count != 2 || !bytes.Equal(passwords[0].Password, a) {
+		t.Errorf("passwords: %v", passwords)
+	}
+}
+
+func concat(blocks ...[]byte) []byte {
+	return bytes.Join(blocks, nil)
+}
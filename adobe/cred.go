@@ -2,6 +2,7 @@ package adobe
 
 import (
 	"encoding/base64"
+	"encoding/binary"
 	"strconv"
 	"strings"
 )
@@ -33,3 +34,13 @@ func ParseRecord(record []string) (*Cred, error) {
 		strings.TrimSpace(record[4]),
 	}, nil
 }
+
+// Blocks splits the encrypted password into 8-byte ECB blocks. A
+// trailing partial block is ignored.
+func (c *Cred) Blocks() []uint64 {
+	blocks := make([]uint64, len(c.Password)/8)
+	for i := range blocks {
+		blocks[i] = binary.BigEndian.Uint64(c.Password[8*i:])
+	}
+	return blocks
+}
@@ -2,6 +2,8 @@ package adobe
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -11,72 +13,382 @@ import (
 
 // CredReader parses a credential dump into records.
 type CredReader struct {
+	// Quarantine, when set, makes the reader lenient: malformed rows
+	// are passed to it and skipped rather than returned as errors, and
+	// an unterminated row is skipped through the next row terminator
+	// or up to the start of the next record.
+	// Read also validates the uid and password of each record.
+	Quarantine *Quarantine
+
+	// MaxLines is the maximum number of lines joined with newlines to
+	// form a row, for fields containing newlines. A row also ends
+	// unterminated at a line that begins a new record. If zero,
+	// DefaultMaxLines is used.
+	MaxLines int
+
 	br     *bufio.Reader
 	record int
 	line   int
+	start  int // first line of the current row
+
+	// deferTotal returns the row count line as a *rowCount rather than
+	// checking it, for readers of a chunk of the dump.
+	deferTotal bool
+
+	row     []byte // row joined from multiple lines
+	long    []byte // line longer than the bufio buffer
+	unread  []byte // line to be returned by the next readLine
+	fields  [5][]byte
+	scratch CredBytes // for ReadInto
+	strs    []byte    // string fields for ReadInto
+}
+
+// CredBytes is a Cred with byte slice fields, as read by
+// CredReader.ReadBytes. Username, Email, and Hint are only valid until
+// the next read and Password is reused across reads.
+type CredBytes struct {
+	UID      int32
+	Username []byte
+	Email    []byte
+	Password []byte
+	Hint     []byte
 }
 
+// DefaultMaxLines is the default maximum number of lines in a row.
+const DefaultMaxLines = 10
+
+var (
+	rowTerminator  = []byte("|--")
+	rowsSelected   = []byte(" rows selected.")
+	fieldSeparator = []byte("-|-")
+)
+
 // NewCredReader constructs a CredReader.
 func NewCredReader(r io.Reader) *CredReader {
-	return &CredReader{bufio.NewReader(r), 0, 0}
+	return &CredReader{br: bufio.NewReader(r)}
 }
 
 // Read reads one record from r.
 func (r *CredReader) Read() ([]string, error) {
-	var line string
-	for line == "" {
-		r.line++
-		l, err := r.br.ReadString('\n')
+	for {
+		row, err := r.readRow()
+		if err != nil {
+			return nil, err
+		}
+		record := strings.SplitN(string(row), "-|-", 5)
+		if len(record) < 5 {
+			if err := r.reject(r.parseError(columnsError(len(record))), row); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if r.Quarantine != nil {
+			if _, err := ParseRecord(record); err != nil {
+				if err := r.reject(r.parseError(err), row); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		r.record++
+		return record, nil
+	}
+}
+
+// ReadBytes reads and parses one record into c without allocating,
+// reusing the capacity of c.Password. It is equivalent to Read followed
+// by ParseRecord, except that parse errors are wrapped in a ParseError.
+func (r *CredReader) ReadBytes(c *CredBytes) error {
+	for {
+		row, err := r.readRow()
 		if err != nil {
-			return nil, &ParseError{r.record, r.line, err}
+			return err
+		}
+		perr := r.parseRow(row, c)
+		if perr == nil {
+			r.record++
+			return nil
+		}
+		if r.Quarantine != nil {
+			if err := r.reject(perr, row); err != nil {
+				return err
+			}
+			continue
+		}
+		// like Read, rows with too few columns are not counted
+		if _, ok := perr.Err.(columnsError); !ok {
+			r.record++
 		}
-		line = l[:len(l)-1]
+		return perr
 	}
+}
+
+// parseRow parses the fields of a row into c.
+func (r *CredReader) parseRow(row []byte, c *CredBytes) *ParseError {
+	n := splitFields(row, &r.fields)
+	if n < 5 {
+		return r.parseError(columnsError(n))
+	}
+	f := &r.fields
+	uid, err := parseUID(f[0])
+	if err != nil {
+		return r.parseError(err)
+	}
+	password := c.Password[:0]
+	if l := base64.StdEncoding.DecodedLen(len(f[3])); cap(password) < l {
+		password = make([]byte, l)
+	}
+	m, err := base64.StdEncoding.Decode(password[:cap(password)], f[3])
+	if err != nil {
+		return r.parseError(err)
+	}
+	c.UID = uid
+	c.Username = bytes.TrimSpace(f[1])
+	c.Email = bytes.TrimSpace(f[2])
+	c.Password = password[:m]
+	c.Hint = bytes.TrimSpace(f[4])
+	return nil
+}
+
+// ReadInto reads and parses one record into c, reusing the capacity of
+// c.Password and allocating at most one string for the text fields. It
+// is equivalent to Read followed by ParseRecord, except that parse
+// errors are wrapped in a ParseError.
+func (r *CredReader) ReadInto(c *Cred) error {
+	cb := &r.scratch
+	if err := r.ReadBytes(cb); err != nil {
+		return err
+	}
+	r.strs = append(append(append(r.strs[:0], cb.Username...), cb.Email...), cb.Hint...)
+	s := string(r.strs)
+	u, e := len(cb.Username), len(cb.Username)+len(cb.Email)
+	c.UID = cb.UID
+	c.Username = s[:u]
+	c.Email = s[u:e]
+	c.Password = append(c.Password[:0], cb.Password...)
+	c.Hint = s[e:]
+	return nil
+}
+
+// readRow reads the next row, without its terminator. The returned
+// slice is only valid until the next read.
+func (r *CredReader) readRow() ([]byte, error) {
+	for {
+		var line []byte
+		for len(line) == 0 {
+			r.line++
+			r.start = r.line
+			l, err := r.readLine()
+			if err != nil {
+				return nil, r.parseError(err)
+			}
+			line = l[:len(l)-1]
+		}
+
+		if bytes.HasSuffix(line, rowTerminator) {
+			return line[:len(line)-len(rowTerminator)], nil
+		}
 
-	if !strings.HasSuffix(line, "|--") {
 		// Exit if row count encountered
-		if strings.HasSuffix(line, " rows selected.") {
-			records, err := strconv.Atoi(line[:len(line)-len(" rows selected.")])
+		if bytes.HasSuffix(line, rowsSelected) {
+			records, err := strconv.Atoi(string(line[:len(line)-len(rowsSelected)]))
 			if err != nil {
-				return nil, &ParseError{-1, r.line, err}
+				return nil, &ParseError{Record: -1, StartLine: r.line, Line: r.line, Err: err}
 			}
-			if records != r.record {
-				return nil, &ParseError{-1, r.line, fmt.Errorf("%d records expected, but %d parsed", records, r.record)}
+			if r.deferTotal {
+				return nil, &rowCount{records, r.record, r.line}
 			}
-			return nil, io.EOF
+			return nil, checkRowCount(records, r.record, r.line)
 		}
 
-		// Join with the next line to make a complete row
-		r.line++
-		next, err := r.br.ReadString('\n')
-		if err != nil {
-			return nil, &ParseError{r.record, r.line, err}
+		// Join with continuation lines to make a complete row
+		r.row = append(r.row[:0], line...)
+		maxLines := r.MaxLines
+		if maxLines <= 0 {
+			maxLines = DefaultMaxLines
+		}
+		for n := 1; n < maxLines; n++ {
+			next, err := r.readContinuation()
+			if err != nil {
+				return nil, err
+			}
+			if next == nil {
+				break
+			}
+			r.row = append(append(r.row, '\n'), next...)
+			if bytes.HasSuffix(r.row, rowTerminator) {
+				return r.row[:len(r.row)-len(rowTerminator)], nil
+			}
 		}
-		line += next[:len(next)-1]
-		if !strings.HasSuffix(line, "|--") {
-			return nil, &ParseError{r.record, r.line, errors.New("unterminated row")}
+
+		perr := r.parseError(errUnterminated)
+		if r.Quarantine == nil {
+			return nil, perr
+		}
+		if err := r.resync(); err != nil {
+			return nil, err
+		}
+		if err := r.reject(perr, r.row); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readContinuation reads the next line of a row, without its newline.
+// It returns nil at a row count line or a line that begins a new
+// record, which is left to be read next.
+func (r *CredReader) readContinuation() ([]byte, error) {
+	r.line++
+	l, err := r.readLine()
+	if err != nil {
+		return nil, r.parseError(err)
+	}
+	l = l[:len(l)-1]
+	if bytes.HasSuffix(l, rowsSelected) || startsRecord(l) {
+		r.unread = append(append(r.unread[:0], l...), '\n')
+		r.line--
+		return nil, nil
+	}
+	return l, nil
+}
+
+// resync appends lines to r.row through the next row terminator, so
+// that reading can continue after an unterminated row. A row count
+// line or a line that begins a new record is left to be read next.
+func (r *CredReader) resync() error {
+	for !bytes.HasSuffix(r.row, rowTerminator) {
+		l, err := r.readContinuation()
+		if err != nil || l == nil {
+			return err
 		}
+		r.row = append(append(r.row, '\n'), l...)
 	}
+	return nil
+}
+
+// startsRecord reports whether a line begins with a uid followed by a
+// field separator, as the first line of a row does.
+func startsRecord(line []byte) bool {
+	i := 0
+	for i < len(line) && '0' <= line[i] && line[i] <= '9' {
+		i++
+	}
+	return i != 0 && bytes.HasPrefix(line[i:], fieldSeparator)
+}
 
-	line = line[:len(line)-len("|--")]
-	record := strings.SplitN(line, "-|-", 5)
-	if len(record) < 5 {
-		return nil, &ParseError{r.record, r.line, fmt.Errorf("only %d columns", len(record))}
+// reject passes a malformed row to the quarantine and counts it as a
+// record, or returns err when not lenient.
+func (r *CredReader) reject(err *ParseError, row []byte) error {
+	if r.Quarantine == nil {
+		return err
 	}
 	r.record++
-	return record, nil
+	return r.Quarantine.Reject(err, row)
+}
+
+// readLine reads a line including its newline. The returned slice is
+// only valid until the next read.
+func (r *CredReader) readLine() ([]byte, error) {
+	if len(r.unread) != 0 {
+		l := r.unread
+		r.unread = r.unread[:0]
+		return l, nil
+	}
+	l, err := r.br.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return l, err
+	}
+	r.long = append(r.long[:0], l...)
+	for err == bufio.ErrBufferFull {
+		l, err = r.br.ReadSlice('\n')
+		r.long = append(r.long, l...)
+	}
+	return r.long, err
+}
+
+// splitFields splits a row into at most 5 fields, like strings.SplitN,
+// and returns the number of fields.
+func splitFields(row []byte, fields *[5][]byte) int {
+	n := 0
+	for ; n < len(fields)-1; n++ {
+		i := bytes.Index(row, fieldSeparator)
+		if i == -1 {
+			break
+		}
+		fields[n] = row[:i]
+		row = row[i+len(fieldSeparator):]
+	}
+	fields[n] = row
+	return n + 1
+}
+
+// parseUID parses a decimal int32 without allocating, falling back to
+// strconv.ParseInt for unusual input so that errors match ParseRecord.
+func parseUID(b []byte) (int32, error) {
+	if len(b) != 0 && len(b) < 10 {
+		var n int32
+		for i, c := range b {
+			if c < '0' || c > '9' {
+				break
+			}
+			n = n*10 + int32(c-'0')
+			if i == len(b)-1 {
+				return n, nil
+			}
+		}
+	}
+	uid, err := strconv.ParseInt(string(b), 10, 32)
+	return int32(uid), err
+}
+
+var errUnterminated = errors.New("unterminated row")
+
+// columnsError is the error for a row with too few columns.
+type columnsError int
+
+func (n columnsError) Error() string {
+	return fmt.Sprintf("only %d columns", int(n))
+}
+
+// checkRowCount checks the row count at the end of the dump against
+// the number of records parsed.
+func checkRowCount(records, parsed, line int) error {
+	if records != parsed {
+		return &ParseError{Record: -1, StartLine: line, Line: line, Err: fmt.Errorf("%d records expected, but %d parsed", records, parsed)}
+	}
+	return io.EOF
+}
+
+// rowCount is a row count line that has not yet been checked.
+type rowCount struct {
+	records int
+	parsed  int
+	line    int
+}
+
+func (c *rowCount) Error() string {
+	return fmt.Sprintf("row count on line %d", c.line)
+}
+
+// parseError constructs a ParseError for the current row.
+func (r *CredReader) parseError(err error) *ParseError {
+	return &ParseError{r.record, r.start, r.line, err}
 }
 
 // ParseError is an error returned during parsing.
 type ParseError struct {
-	Record int
-	Line   int
-	Err    error
+	Record    int
+	StartLine int // first line of the row
+	Line      int // line on which the error was found
+	Err       error
 }
 
 func (err *ParseError) Error() string {
 	if err.Record == -1 {
 		return fmt.Sprintf("record total on line %d: %v", err.Line, err.Err)
 	}
+	if err.StartLine != err.Line {
+		return fmt.Sprintf("record %d on lines %d-%d: %v", err.Record, err.StartLine, err.Line, err.Err)
+	}
 	return fmt.Sprintf("record %d on line %d: %v", err.Record, err.Line, err.Err)
 }
@@ -0,0 +1,124 @@
+package adobe
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReadInto(t *testing.T) {
+	long := testDump(3, "") + "3-|-u-|-e-|-AAAAAAAAAAA=-|-" + strings.Repeat("long hint ", 1000) + "|--\n4 rows selected.\n"
+	for i, dump := range append(parallelTests, long) {
+		want := readSequential(dump, nil)
+
+		var got []string
+		cr := NewCredReader(strings.NewReader(dump))
+		var c Cred
+		for {
+			err := cr.ReadInto(&c)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				got = append(got, "error: "+err.Error())
+				if perr, ok := err.(*ParseError); ok && (perr.Err == io.EOF || perr.Record == -1) {
+					break
+				}
+				continue
+			}
+			got = append(got, fmt.Sprint(c))
+		}
+		if !equalItems(got, want) {
+			t.Errorf("#%d: ReadInto:\ngot  %q\nwant %q", i, got, want)
+		}
+
+		got = got[:0]
+		cr = NewCredReader(strings.NewReader(dump))
+		var cb CredBytes
+		for {
+			err := cr.ReadBytes(&cb)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				got = append(got, "error: "+err.Error())
+				if perr, ok := err.(*ParseError); ok && (perr.Err == io.EOF || perr.Record == -1) {
+					break
+				}
+				continue
+			}
+			c := Cred{cb.UID, string(cb.Username), string(cb.Email), cb.Password, string(cb.Hint)}
+			got = append(got, fmt.Sprint(c))
+		}
+		if !equalItems(got, want) {
+			t.Errorf("#%d: ReadBytes:\ngot  %q\nwant %q", i, got, want)
+		}
+	}
+}
+
+func TestParseUID(t *testing.T) {
+	for _, s := range []string{"0", "123", "999999999", "2147483647", "2147483648", "-5", "+5", "", "1a", " 1", "99999999999"} {
+		uid, err := parseUID([]byte(s))
+		want, wantErr := strconv.ParseInt(s, 10, 32)
+		if uid != int32(want) || fmt.Sprint(err) != fmt.Sprint(wantErr) {
+			t.Errorf("parseUID(%q) = %d, %v, want %d, %v", s, uid, err, want, wantErr)
+		}
+	}
+}
+
+func TestReadContinuationLines(t *testing.T) {
+	dump := "0-|-u-|-e-|-AAAAAAAAAAA=-|-one\ntwo\nthree|--\n" +
+		"1-|-u-|-e-|-AAAAAAAAAAA=-|-a\nb\nc\nd|--\n" +
+		"2 rows selected.\n"
+
+	cr := NewCredReader(strings.NewReader(dump))
+	for i, want := range []string{"one\ntwo\nthree", "a\nb\nc\nd"} {
+		record, err := cr.Read()
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if record[4] != want {
+			t.Errorf("#%d: hint %q want %q", i, record[4], want)
+		}
+	}
+	if _, err := cr.Read(); err != io.EOF {
+		t.Errorf("result: %v want EOF", err)
+	}
+
+	cr = NewCredReader(strings.NewReader(dump))
+	cr.MaxLines = 3
+	if _, err := cr.Read(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := cr.Read()
+	want := "record 1 on lines 4-6: unterminated row"
+	if err == nil || err.Error() != want {
+		t.Errorf("result: %v want %s", err, want)
+	}
+
+	// the start of a new record ends an unterminated row
+	cr = NewCredReader(strings.NewReader("0-|-a\nb\n1-|-u-|-e-|-AAAAAAAAAAA=-|-hint|--\n2 rows selected.\n"))
+	_, err = cr.Read()
+	want = "record 0 on lines 1-2: unterminated row"
+	if err == nil || err.Error() != want {
+		t.Errorf("result: %v want %s", err, want)
+	}
+	if record, err := cr.Read(); err != nil || record[0] != "1" {
+		t.Errorf("result: %q, %v want record 1", record, err)
+	}
+
+	// a row count line ends an unterminated row
+	cr = NewCredReader(strings.NewReader("0-|-a\nb\n1 rows selected.\n"))
+	_, err = cr.Read()
+	want = "record 0 on lines 1-2: unterminated row"
+	if err == nil || err.Error() != want {
+		t.Errorf("result: %v want %s", err, want)
+	}
+	_, err = cr.Read()
+	want = "record total on line 3: 1 records expected, but 0 parsed"
+	if err == nil || err.Error() != want {
+		t.Errorf("result: %v want %s", err, want)
+	}
+}
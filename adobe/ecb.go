@@ -0,0 +1,112 @@
+package adobe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// BlockCipher encrypts 8-byte blocks, as des.Cipher and
+// des.TripleDESCipher do.
+type BlockCipher interface {
+	EncryptBlock(block uint64) uint64
+	DecryptBlock(block uint64) uint64
+}
+
+// Padding is the scheme used to pad a password to a multiple of the
+// block size. Both schemes add 1 to 8 bytes, so a password that fills
+// its final block is followed by a block of only padding.
+type Padding int
+
+const (
+	// PKCS5Padding pads with n bytes of value n.
+	PKCS5Padding Padding = iota
+	// NullPadding pads with zero bytes.
+	NullPadding
+)
+
+func (p Padding) String() string {
+	switch p {
+	case PKCS5Padding:
+		return "PKCS#5"
+	case NullPadding:
+		return "null"
+	}
+	return fmt.Sprintf("Padding(%d)", int(p))
+}
+
+var (
+	errPasswordLength = errors.New("adobe: encrypted password not a multiple of the block size")
+	errPadding        = errors.New("adobe: invalid padding")
+)
+
+// EncryptPassword pads a plaintext password and encrypts it in ECB
+// mode.
+func EncryptPassword(c BlockCipher, plaintext []byte, p Padding) []byte {
+	b := pad(plaintext, p)
+	for i := 0; i < len(b); i += 8 {
+		binary.BigEndian.PutUint64(b[i:], c.EncryptBlock(binary.BigEndian.Uint64(b[i:])))
+	}
+	return b
+}
+
+// pad copies a plaintext password with padding appended.
+func pad(plaintext []byte, p Padding) []byte {
+	n := 8 - len(plaintext)%8
+	b := make([]byte, len(plaintext)+n)
+	copy(b, plaintext)
+	if p == PKCS5Padding {
+		for i := len(plaintext); i < len(b); i++ {
+			b[i] = byte(n)
+		}
+	}
+	return b
+}
+
+// DecryptPassword decrypts an encrypted password in ECB mode and
+// removes its padding. An error is returned when the length is not a
+// positive multiple of the block size or the padding is invalid, which
+// usually means the key is wrong.
+func DecryptPassword(c BlockCipher, password []byte, p Padding) ([]byte, error) {
+	if len(password) == 0 || len(password)%8 != 0 {
+		return nil, errPasswordLength
+	}
+	b := make([]byte, len(password))
+	for i := 0; i < len(b); i += 8 {
+		binary.BigEndian.PutUint64(b[i:], c.DecryptBlock(binary.BigEndian.Uint64(password[i:])))
+	}
+	n, ok := unpad(b, p)
+	if !ok {
+		return nil, errPadding
+	}
+	return b[:n], nil
+}
+
+// unpad returns the length of a decrypted password without its padding.
+func unpad(b []byte, p Padding) (int, bool) {
+	switch p {
+	case PKCS5Padding:
+		n := int(b[len(b)-1])
+		if n < 1 || n > 8 {
+			return 0, false
+		}
+		for _, x := range b[len(b)-n:] {
+			if int(x) != n {
+				return 0, false
+			}
+		}
+		return len(b) - n, true
+	case NullPadding:
+		n := len(b)
+		for n > 0 && len(b)-n < 8 && b[n-1] == 0 {
+			n--
+		}
+		// the padding is at least one byte and does not continue into
+		// the preceding block
+		if n == len(b) || n > 0 && b[n-1] == 0 {
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
+}
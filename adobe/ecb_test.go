@@ -0,0 +1,71 @@
+package adobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+func TestPasswordRoundTrip(t *testing.T) {
+	ciphers := []BlockCipher{
+		des.NewCipher(0x0123456789abcdef),
+		des.NewTripleDESCipher([3]uint64{0x0123456789abcdef, 0x23456789abcdef01, 0x456789abcdef0123}),
+	}
+	passwords := []string{"", "a", "1234567", "12345678", "123456789", "correct horse battery staple"}
+	for _, c := range ciphers {
+		for _, p := range []Padding{PKCS5Padding, NullPadding} {
+			for _, pw := range passwords {
+				enc := EncryptPassword(c, []byte(pw), p)
+				if r := PasswordLength(enc); len(pw) < r.Min || len(pw) > r.Max {
+					t.Errorf("%v %q: length %d outside %v", p, pw, len(enc), r)
+				}
+				dec, err := DecryptPassword(c, enc, p)
+				if err != nil || string(dec) != pw {
+					t.Errorf("%v %q: result: %q, %v", p, pw, dec, err)
+				}
+			}
+		}
+	}
+}
+
+func TestPasswordPadding(t *testing.T) {
+	c := des.NewCipher(0x0123456789abcdef)
+	enc := func(plain string) []byte {
+		b := []byte(plain)
+		for i := 0; i < len(b); i += 8 {
+			binary.BigEndian.PutUint64(b[i:], c.EncryptBlock(binary.BigEndian.Uint64(b[i:])))
+		}
+		return b
+	}
+	if got := EncryptPassword(c, []byte("1234567"), PKCS5Padding); !bytes.Equal(got, enc("1234567\x01")) {
+		t.Errorf("PKCS#5 encryption: %x", got)
+	}
+
+	tests := []struct {
+		plain string
+		p     Padding
+		want  string
+		ok    bool
+	}{
+		{"1234567\x01", PKCS5Padding, "1234567", true},
+		{"12345678\x08\x08\x08\x08\x08\x08\x08\x08", PKCS5Padding, "12345678", true},
+		{"1234567\x00", PKCS5Padding, "", false},
+		{"1234567\x09", PKCS5Padding, "", false},
+		{"123456\x01\x02", PKCS5Padding, "", false},
+		{"123\x00\x00\x00\x00\x00", NullPadding, "123", true},
+		{"\x00\x00\x00\x00\x00\x00\x00\x00", NullPadding, "", true},
+		{"12345678", NullPadding, "", false},
+		{"1234567\x00\x00\x00\x00\x00\x00\x00\x00\x00", NullPadding, "", false},
+	}
+	for i, tt := range tests {
+		dec, err := DecryptPassword(c, enc(tt.plain), tt.p)
+		if (err == nil) != tt.ok || string(dec) != tt.want {
+			t.Errorf("#%d: result: %q, %v want: %q, %t", i, dec, err, tt.want, tt.ok)
+		}
+	}
+	if _, err := DecryptPassword(c, make([]byte, 12), PKCS5Padding); err == nil {
+		t.Error("partial block decrypted")
+	}
+}
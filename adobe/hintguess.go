@@ -0,0 +1,138 @@
+package adobe
+
+import (
+	"encoding/binary"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+// hintSuffixes are digits and symbols commonly appended to a word to
+// satisfy password rules.
+var hintSuffixes = []string{"", "1", "12", "123", "1234", "01", "2013", "!"}
+
+// hintPrefixes are phrases that introduce the password in a hint, such
+// as "same as my email" or "it's fido".
+var hintPrefixes = []string{"same as ", "same ", "my ", "the ", "it's ", "its ", "is "}
+
+const rhymesWith = "rhymes with "
+
+// HintGuesses returns candidate plaintext passwords suggested by a
+// normalized hint: the hint itself, without spaces, and its last word,
+// after stripping introductory phrases, in lower, title, and upper case
+// and with common suffixes. A hint of the form "rhymes with x" also
+// suggests x with its leading consonants replaced.
+func HintGuesses(hint string) []string {
+	rhyme := strings.HasPrefix(hint, rhymesWith)
+	hint = trimHintPrefixes(strings.TrimPrefix(hint, rhymesWith))
+	if hint == "" {
+		return nil
+	}
+	bases := []string{hint, strings.ReplaceAll(hint, " ", "")}
+	if i := strings.LastIndexByte(hint, ' '); i != -1 {
+		bases = append(bases, hint[i+1:])
+	}
+	if rhyme {
+		bases = append(bases, rhymes(bases[len(bases)-1])...)
+	}
+	return expandGuesses(bases)
+}
+
+// trimHintPrefixes strips the introductory phrases from a hint.
+func trimHintPrefixes(hint string) string {
+	hint += " "
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, prefix := range hintPrefixes {
+			if strings.HasPrefix(hint, prefix) {
+				hint = hint[len(prefix):]
+				trimmed = true
+			}
+		}
+	}
+	return strings.TrimSuffix(hint, " ")
+}
+
+// expandGuesses returns each base in lower, title, and upper case and
+// with common suffixes, without duplicates.
+func expandGuesses(bases []string) []string {
+	seen := make(map[string]bool)
+	var guesses []string
+	for _, base := range bases {
+		if base == "" {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(base)
+		title := string(unicode.ToUpper(r)) + base[size:]
+		for _, word := range []string{base, title, strings.ToUpper(base)} {
+			for _, suffix := range hintSuffixes {
+				guess := word + suffix
+				if !seen[guess] {
+					seen[guess] = true
+					guesses = append(guesses, guess)
+				}
+			}
+		}
+	}
+	return guesses
+}
+
+// accountHints are normalized hints, after stripping introductory
+// phrases, that refer to the user's own email or username.
+var accountHints = map[string]bool{
+	"email": true, "e-mail": true, "email address": true, "mail": true,
+	"username": true, "user name": true, "user": true, "login": true,
+	"user id": true, "userid": true, "id": true,
+}
+
+// AccountGuesses returns candidate plaintext passwords for a credential
+// whose hint refers to its own account, such as "same as email" or
+// "username": the username, the email, and the local part of the email,
+// with the variants of HintGuesses. It returns nil for other hints.
+func AccountGuesses(c *Cred) []string {
+	if !accountHints[trimHintPrefixes(NormalizeHint(c.Hint))] {
+		return nil
+	}
+	bases := []string{c.Username, c.Email}
+	if i := strings.LastIndexByte(c.Email, '@'); i != -1 {
+		bases = append(bases, c.Email[:i])
+	}
+	return expandGuesses(bases)
+}
+
+// rhymes returns the words formed by replacing the leading consonants
+// of a word with each single consonant.
+func rhymes(word string) []string {
+	i := strings.IndexAny(word, "aeiouy")
+	if i == -1 {
+		return nil
+	}
+	var words []string
+	for c := 'b'; c <= 'z'; c++ {
+		if !strings.ContainsRune("aeiou", c) && word[:i] != string(c) {
+			words = append(words, string(c)+word[i:])
+		}
+	}
+	return words
+}
+
+// GuessPairs returns the plaintext and cipher text block pairs given by
+// guessing that an encrypted password has the plaintext guess. It
+// returns nil when the padded guess differs in length from the
+// encrypted password.
+func GuessPairs(password []byte, guess string, p Padding) []des.Pair {
+	b := pad([]byte(guess), p)
+	if len(b) != len(password) {
+		return nil
+	}
+	pairs := make([]des.Pair, len(b)/8)
+	for i := range pairs {
+		pairs[i] = des.Pair{
+			In:  binary.BigEndian.Uint64(b[8*i:]),
+			Out: binary.BigEndian.Uint64(password[8*i:]),
+		}
+	}
+	return pairs
+}
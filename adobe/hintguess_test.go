@@ -0,0 +1,98 @@
+package adobe
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+func TestHintGuesses(t *testing.T) {
+	tests := []struct {
+		hint    string
+		want    []string
+		notWant []string
+	}{
+		{"fido", []string{"fido", "Fido", "FIDO", "fido123", "Fido1", "FIDO!"}, nil},
+		{"same as my dog", []string{"dog", "Dog1"}, []string{"same as my dog"}},
+		{"dog name", []string{"dog name", "dogname", "name", "Dogname12"}, nil},
+		{"rhymes with cat", []string{"cat", "bat", "hat", "Rat1"}, []string{"rhymes with cat", "aat"}},
+		{"rhymes with shoe", []string{"shoe", "hoe", "toe"}, nil},
+		{"éclair", []string{"éclair", "Éclair", "ÉCLAIR1"}, nil},
+	}
+	for _, tt := range tests {
+		guesses := make(map[string]bool)
+		for _, g := range HintGuesses(tt.hint) {
+			if guesses[g] {
+				t.Errorf("%q: duplicate guess %q", tt.hint, g)
+			}
+			if !utf8.ValidString(g) {
+				t.Errorf("%q: invalid UTF-8 guess %q", tt.hint, g)
+			}
+			guesses[g] = true
+		}
+		for _, w := range tt.want {
+			if !guesses[w] {
+				t.Errorf("%q: missing guess %q", tt.hint, w)
+			}
+		}
+		for _, w := range tt.notWant {
+			if guesses[w] {
+				t.Errorf("%q: unexpected guess %q", tt.hint, w)
+			}
+		}
+	}
+	if g := HintGuesses("same as"); len(g) != 0 {
+		t.Errorf("empty hint: %q", g)
+	}
+}
+
+func TestAccountGuesses(t *testing.T) {
+	tests := []struct {
+		hint string
+		want []string
+	}{
+		{"Same as email", []string{"fido@example.com", "fido", "Fido1", "FIDO"}},
+		{"my user name", []string{"rover", "Rover123", "fido"}},
+		{"login!", []string{"rover", "fido@example.com"}},
+		{"same as my dog", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		c := &Cred{Username: "rover", Email: "fido@example.com", Hint: tt.hint}
+		guesses := make(map[string]bool)
+		for _, g := range AccountGuesses(c) {
+			guesses[g] = true
+		}
+		if tt.want == nil && len(guesses) != 0 {
+			t.Errorf("%q: unexpected guesses %v", tt.hint, guesses)
+		}
+		for _, w := range tt.want {
+			if !guesses[w] {
+				t.Errorf("%q: missing guess %q", tt.hint, w)
+			}
+		}
+	}
+}
+
+func TestGuessPairs(t *testing.T) {
+	c := des.NewTripleDESCipher([3]uint64{0x0123456789abcdef, 0x23456789abcdef01, 0x0123456789abcdef})
+	for _, p := range []Padding{PKCS5Padding, NullPadding} {
+		password := EncryptPassword(c, []byte("password123"), p)
+		if pairs := GuessPairs(password, "pass", p); pairs != nil {
+			t.Errorf("%v: pairs for guess of wrong length: %x", p, pairs)
+		}
+		pairs := GuessPairs(password, "password123", p)
+		if len(pairs) != 2 {
+			t.Fatalf("%v: %d pairs", p, len(pairs))
+		}
+		if pairs[0].In != 0x70617373776f7264 { // "password"
+			t.Errorf("%v: first plaintext block: %x", p, pairs[0].In)
+		}
+		for i, pair := range pairs {
+			if out := c.EncryptBlock(pair.In); out != pair.Out {
+				t.Errorf("%v: pair %d: %x encrypts to %x want %x", p, i, pair.In, out, pair.Out)
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package adobe
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// HintAggregator pools the plaintext hints of users who share an
+// encrypted password or password block, so that the most common hints
+// for a cipher text can be used to guess its plaintext.
+type HintAggregator struct {
+	passwords map[string]*hintTally
+	blocks    map[uint64]*hintTally
+}
+
+type hintTally struct {
+	users int
+	hints map[string]int
+}
+
+// HintGroup is the set of hints for an encrypted password or block.
+type HintGroup struct {
+	Cipher []byte      // encrypted password or 8-byte block
+	Users  int         // users sharing the cipher text
+	Hints  []HintCount // most common first
+}
+
+// HintCount is the number of users giving a normalized hint.
+type HintCount struct {
+	Hint  string
+	Count int
+}
+
+// NewHintAggregator constructs a HintAggregator.
+func NewHintAggregator() *HintAggregator {
+	return &HintAggregator{make(map[string]*hintTally), make(map[uint64]*hintTally)}
+}
+
+// Add pools the hint of a credential with those of other users sharing
+// its password and each of its password blocks.
+func (h *HintAggregator) Add(c *Cred) {
+	if len(c.Password) == 0 {
+		return
+	}
+	hint := NormalizeHint(c.Hint)
+	t, ok := h.passwords[string(c.Password)]
+	if !ok {
+		t = &hintTally{hints: make(map[string]int)}
+		h.passwords[string(c.Password)] = t
+	}
+	t.add(hint)
+	for _, block := range c.Blocks() {
+		t, ok := h.blocks[block]
+		if !ok {
+			t = &hintTally{hints: make(map[string]int)}
+			h.blocks[block] = t
+		}
+		t.add(hint)
+	}
+}
+
+func (t *hintTally) add(hint string) {
+	t.users++
+	if hint != "" {
+		t.hints[hint]++
+	}
+}
+
+// PasswordGroups returns the hint groups for passwords shared by at
+// least minUsers users, with at most top hints each, ordered by
+// decreasing users.
+func (h *HintAggregator) PasswordGroups(minUsers, top int) []HintGroup {
+	var groups []HintGroup
+	for p, t := range h.passwords {
+		if t.users >= minUsers {
+			groups = append(groups, t.group([]byte(p), top))
+		}
+	}
+	sortHintGroups(groups)
+	return groups
+}
+
+// BlockGroups returns the hint groups for blocks shared by at least
+// minUsers users, with at most top hints each, ordered by decreasing
+// users.
+func (h *HintAggregator) BlockGroups(minUsers, top int) []HintGroup {
+	var groups []HintGroup
+	for b, t := range h.blocks {
+		if t.users >= minUsers {
+			var block [8]byte
+			binary.BigEndian.PutUint64(block[:], b)
+			groups = append(groups, t.group(block[:], top))
+		}
+	}
+	sortHintGroups(groups)
+	return groups
+}
+
+func (t *hintTally) group(cipher []byte, top int) HintGroup {
+	hints := make([]HintCount, 0, len(t.hints))
+	for hint, n := range t.hints {
+		hints = append(hints, HintCount{hint, n})
+	}
+	sort.Slice(hints, func(i, j int) bool {
+		if hints[i].Count != hints[j].Count {
+			return hints[i].Count > hints[j].Count
+		}
+		return hints[i].Hint < hints[j].Hint
+	})
+	if len(hints) > top {
+		hints = hints[:top]
+	}
+	return HintGroup{cipher, t.users, hints}
+}
+
+func sortHintGroups(groups []HintGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Users != groups[j].Users {
+			return groups[i].Users > groups[j].Users
+		}
+		return string(groups[i].Cipher) < string(groups[j].Cipher)
+	})
+}
+
+// NormalizeHint lowercases a hint, collapses runs of whitespace, and
+// trims surrounding punctuation, so that trivially different hints are
+// pooled together.
+func NormalizeHint(hint string) string {
+	hint = strings.Join(strings.Fields(strings.ToLower(hint)), " ")
+	return strings.TrimFunc(hint, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+}
+
+// WriteHintGroups writes hint groups as tab-separated lines of users,
+// base64 cipher text, hint count, and hint, with one line per hint.
+func WriteHintGroups(w io.Writer, groups []HintGroup) error {
+	bw := bufio.NewWriter(w)
+	for _, g := range groups {
+		cipher := base64.StdEncoding.EncodeToString(g.Cipher)
+		for _, h := range g.Hints {
+			if _, err := fmt.Fprintf(bw, "%d\t%s\t%d\t%s\n", g.Users, cipher, h.Count, h.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
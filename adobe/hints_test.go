@@ -0,0 +1,52 @@
+package adobe
+
+import "testing"
+
+func TestNormalizeHint(t *testing.T) {
+	for _, tt := range []struct{ hint, want string }{
+		{"", ""},
+		{"  My  Dog's\tName ", "my dog's name"},
+		{"\"the usual!\"", "the usual"},
+		{"...", ""},
+		{"1 2 3", "1 2 3"},
+	} {
+		if got := NormalizeHint(tt.hint); got != tt.want {
+			t.Errorf("NormalizeHint(%q) = %q, want %q", tt.hint, got, tt.want)
+		}
+	}
+}
+
+func TestHintAggregator(t *testing.T) {
+	a := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	b := []byte{9, 10, 11, 12, 13, 14, 15, 16}
+	h := NewHintAggregator()
+	for _, c := range []Cred{
+		{Password: a, Hint: "Usual"},
+		{Password: a, Hint: "usual."},
+		{Password: a, Hint: "numbers"},
+		{Password: a, Hint: ""},
+		{Password: concat(a, b), Hint: "numbers"},
+		{Password: b, Hint: "other"},
+	} {
+		h.Add(&c)
+	}
+
+	groups := h.PasswordGroups(2, 1)
+	if len(groups) != 1 {
+		t.Fatalf("password groups: %v", groups)
+	}
+	if g := groups[0]; string(g.Cipher) != string(a) || g.Users != 4 ||
+		len(g.Hints) != 1 || g.Hints[0] != (HintCount{"usual", 2}) {
+		t.Errorf("password group: %v", g)
+	}
+
+	groups = h.BlockGroups(1, 5)
+	if len(groups) != 2 {
+		t.Fatalf("block groups: %v", groups)
+	}
+	want := []HintCount{{"numbers", 2}, {"usual", 2}}
+	if g := groups[0]; string(g.Cipher) != string(a) || g.Users != 5 || len(g.Hints) != 2 ||
+		g.Hints[0] != want[0] || g.Hints[1] != want[1] {
+		t.Errorf("block group: %v", g)
+	}
+}
@@ -0,0 +1,162 @@
+package adobe
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+// Interpretation is a hypothesis for how the passwords were encrypted:
+// a cipher, its keys, and the byte order of blocks.
+type Interpretation struct {
+	Name   string
+	Cipher BlockCipher
+}
+
+// Interpretations returns the interpretations of up to three keys: DES
+// with each key, two-key Triple DES with each ordered pair, and
+// three-key Triple DES with each ordering, both in EDE and EEE modes.
+// Each is given with big and little endian blocks and keys.
+func Interpretations(keys []uint64) []Interpretation {
+	var base []Interpretation
+	for _, order := range []struct {
+		name string
+		swap bool
+	}{{"", false}, {", little endian keys", true}} {
+		ks := make([]uint64, len(keys))
+		for i, k := range keys {
+			ks[i] = k
+			if order.swap {
+				ks[i] = bits.ReverseBytes64(k)
+			}
+		}
+		for i := range ks {
+			base = append(base, Interpretation{fmt.Sprintf("DES K%d%s", i+1, order.name), des.NewCipher(ks[i])})
+		}
+		for i := range ks {
+			for j := range ks {
+				if i == j {
+					continue
+				}
+				k := [3]uint64{ks[i], ks[j], ks[i]}
+				names := fmt.Sprintf("K%d,K%d,K%d%s", i+1, j+1, i+1, order.name)
+				base = append(base,
+					Interpretation{"3DES EDE " + names, des.NewTripleDESCipher(k)},
+					Interpretation{"3DES EEE " + names, newEEE(k)})
+				for l := range ks {
+					if l == i || l == j {
+						continue
+					}
+					k := [3]uint64{ks[i], ks[j], ks[l]}
+					names := fmt.Sprintf("K%d,K%d,K%d%s", i+1, j+1, l+1, order.name)
+					base = append(base,
+						Interpretation{"3DES EDE " + names, des.NewTripleDESCipher(k)},
+						Interpretation{"3DES EEE " + names, newEEE(k)})
+				}
+			}
+		}
+	}
+
+	interps := make([]Interpretation, 0, 2*len(base))
+	for _, in := range base {
+		interps = append(interps, in, Interpretation{in.Name + ", little endian blocks", littleEndian{in.Cipher}})
+	}
+	return interps
+}
+
+// eee is Triple DES in encrypt-encrypt-encrypt mode.
+type eee [3]*des.Cipher
+
+func newEEE(key [3]uint64) eee {
+	return eee{des.NewCipher(key[0]), des.NewCipher(key[1]), des.NewCipher(key[2])}
+}
+
+func (c eee) EncryptBlock(block uint64) uint64 {
+	return c[2].EncryptBlock(c[1].EncryptBlock(c[0].EncryptBlock(block)))
+}
+
+func (c eee) DecryptBlock(block uint64) uint64 {
+	return c[0].DecryptBlock(c[1].DecryptBlock(c[2].DecryptBlock(block)))
+}
+
+// littleEndian reads and writes blocks with the bytes reversed.
+type littleEndian struct {
+	c BlockCipher
+}
+
+func (c littleEndian) EncryptBlock(block uint64) uint64 {
+	return bits.ReverseBytes64(c.c.EncryptBlock(bits.ReverseBytes64(block)))
+}
+
+func (c littleEndian) DecryptBlock(block uint64) uint64 {
+	return bits.ReverseBytes64(c.c.DecryptBlock(bits.ReverseBytes64(block)))
+}
+
+// HypothesisScore counts the encrypted passwords that decrypt to
+// plausible plaintexts under an interpretation.
+type HypothesisScore struct {
+	Name      string
+	Passwords int // passwords decrypted
+	PKCS5     int // passwords with valid PKCS#5 padding
+	Null      int // passwords with valid null padding
+	Printable int // padded passwords of only printable ASCII
+}
+
+// HypothesisTester scores interpretations against encrypted passwords.
+// The correct interpretation decrypts nearly all passwords to
+// printable plaintexts with valid padding, while a wrong one rarely
+// does.
+type HypothesisTester struct {
+	interps []Interpretation
+	scores  []HypothesisScore
+}
+
+// NewHypothesisTester constructs a HypothesisTester.
+func NewHypothesisTester(interps []Interpretation) *HypothesisTester {
+	h := &HypothesisTester{interps, make([]HypothesisScore, len(interps))}
+	for i, in := range interps {
+		h.scores[i].Name = in.Name
+	}
+	return h
+}
+
+// Add decrypts the password of a credential under each
+// interpretation.
+func (h *HypothesisTester) Add(c *Cred) {
+	if len(c.Password) == 0 || len(c.Password)%8 != 0 {
+		return
+	}
+	for i, in := range h.interps {
+		s := &h.scores[i]
+		s.Passwords++
+		plain, err := DecryptPassword(in.Cipher, c.Password, PKCS5Padding)
+		if err == nil {
+			s.PKCS5++
+		} else if plain, err = DecryptPassword(in.Cipher, c.Password, NullPadding); err == nil {
+			s.Null++
+		}
+		if err == nil && printable(plain) {
+			s.Printable++
+		}
+	}
+}
+
+// Scores returns the scores ordered by decreasing printable passwords.
+func (h *HypothesisTester) Scores() []HypothesisScore {
+	scores := append([]HypothesisScore(nil), h.scores...)
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Printable > scores[j].Printable
+	})
+	return scores
+}
+
+func printable(b []byte) bool {
+	for _, c := range b {
+		if c < ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
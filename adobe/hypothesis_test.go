@@ -0,0 +1,61 @@
+package adobe
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+	"testing"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+func TestInterpretations(t *testing.T) {
+	keys := []uint64{0x0123456789abcdef, 0x23456789abcdef01, 0x456789abcdef0123}
+	names := make(map[string]bool)
+	for _, in := range Interpretations(keys) {
+		if names[in.Name] {
+			t.Errorf("duplicate interpretation %q", in.Name)
+		}
+		names[in.Name] = true
+	}
+	// per key byte order and block byte order: 3 DES, 6 two-key and 6
+	// three-key orderings in each of 2 modes
+	if want := 2 * 2 * (3 + 2*6 + 2*6); len(names) != want {
+		t.Errorf("%d interpretations want %d", len(names), want)
+	}
+	if n := len(Interpretations(keys[:1])); n != 4 {
+		t.Errorf("%d interpretations of one key want 4", n)
+	}
+}
+
+func TestHypothesisTester(t *testing.T) {
+	keys := []uint64{0x0123456789abcdef, 0x23456789abcdef01}
+	tests := []struct {
+		name   string
+		cipher BlockCipher
+		p      Padding
+	}{
+		{"DES K1", des.NewCipher(keys[0]), PKCS5Padding},
+		{"3DES EDE K2,K1,K2", des.NewTripleDESCipher([3]uint64{keys[1], keys[0], keys[1]}), PKCS5Padding},
+		{"3DES EEE K1,K2,K1, little endian blocks", littleEndian{newEEE([3]uint64{keys[0], keys[1], keys[0]})}, NullPadding},
+		{"DES K2, little endian keys", des.NewCipher(bits.ReverseBytes64(keys[1])), PKCS5Padding},
+	}
+	for _, tt := range tests {
+		h := NewHypothesisTester(Interpretations(keys))
+		for i := 0; i < 50; i++ {
+			pw := fmt.Sprintf("password%d", i*i)
+			h.Add(&Cred{Password: EncryptPassword(tt.cipher, []byte(pw), tt.p)})
+		}
+		scores := h.Scores()
+		best := scores[0]
+		if best.Name != tt.name || best.Printable != 50 || best.Passwords != 50 {
+			t.Errorf("%s: best: %+v", tt.name, best)
+		}
+		if tt.p == NullPadding && best.Null != 50 || tt.p == PKCS5Padding && best.PKCS5 != 50 {
+			t.Errorf("%s: padding: %+v", tt.name, best)
+		}
+		if second := scores[1]; second.Printable > 5 && !strings.HasPrefix(second.Name, "3DES") {
+			t.Errorf("%s: second best: %+v", tt.name, second)
+		}
+	}
+}
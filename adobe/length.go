@@ -0,0 +1,119 @@
+package adobe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// LengthRange is an inclusive range of possible plaintext password
+// lengths.
+type LengthRange struct {
+	Min, Max int
+}
+
+// InvalidLength is the empty range of lengths of an encrypted password
+// that is not a positive multiple of the block size.
+var InvalidLength = LengthRange{0, -1}
+
+// Valid returns whether the range contains any length.
+func (r LengthRange) Valid() bool {
+	return r.Min <= r.Max
+}
+
+// Exact returns whether the range contains a single length.
+func (r LengthRange) Exact() bool {
+	return r.Min == r.Max
+}
+
+func (r LengthRange) String() string {
+	if !r.Valid() {
+		return "invalid"
+	}
+	if r.Exact() {
+		return fmt.Sprint(r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// PasswordLength returns the possible plaintext lengths of an encrypted
+// password. Passwords are padded with 1 to 8 bytes to a multiple of the
+// block size, so a plaintext that fills its final block is followed by
+// a block of only padding. InvalidLength is returned when the length
+// is not a positive multiple of the block size.
+func PasswordLength(password []byte) LengthRange {
+	n := len(password)
+	if n == 0 || n%8 != 0 {
+		return InvalidLength
+	}
+	return LengthRange{n - 8, n - 1}
+}
+
+// LengthInferrer narrows plaintext length ranges using final cipher
+// text blocks whose padding is known.
+type LengthInferrer struct {
+	final map[uint64]int
+}
+
+// NewLengthInferrer constructs a LengthInferrer.
+func NewLengthInferrer() *LengthInferrer {
+	return &LengthInferrer{make(map[uint64]int)}
+}
+
+// AddFinalBlock records that the final block of a password with the
+// given cipher text holds n bytes of plaintext before the padding. The
+// block of only padding has n = 0.
+func (l *LengthInferrer) AddFinalBlock(block uint64, n int) {
+	l.final[block] = n
+}
+
+// Length returns the possible plaintext lengths of an encrypted
+// password, which is exact when its final block is known.
+func (l *LengthInferrer) Length(password []byte) LengthRange {
+	r := PasswordLength(password)
+	if !r.Valid() {
+		return r
+	}
+	final := binary.BigEndian.Uint64(password[r.Min:])
+	if n, ok := l.final[final]; ok {
+		return LengthRange{r.Min + n, r.Min + n}
+	}
+	return r
+}
+
+// LengthCount is the number of passwords in a length range.
+type LengthCount struct {
+	Range LengthRange
+	Count int
+}
+
+// LengthStats is a histogram of plaintext length ranges.
+type LengthStats struct {
+	counts map[LengthRange]int
+}
+
+// NewLengthStats constructs a LengthStats.
+func NewLengthStats() *LengthStats {
+	return &LengthStats{make(map[LengthRange]int)}
+}
+
+// Add counts a length range.
+func (s *LengthStats) Add(r LengthRange) {
+	s.counts[r]++
+}
+
+// Counts returns the counts ordered by range.
+func (s *LengthStats) Counts() []LengthCount {
+	counts := make([]LengthCount, 0, len(s.counts))
+	for r, n := range s.counts {
+		counts = append(counts, LengthCount{r, n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		ri, rj := counts[i].Range, counts[j].Range
+		if ri.Min != rj.Min {
+			return ri.Min < rj.Min
+		}
+		return ri.Max < rj.Max
+	})
+	return counts
+}
@@ -0,0 +1,35 @@
+package adobe
+
+import "testing"
+
+func TestPasswordLength(t *testing.T) {
+	pad := []byte{0xc3, 0x8e, 0x5c, 0x2a, 0x11, 0x07, 0x90, 0x4d}
+	other := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	l := NewLengthInferrer()
+	l.AddFinalBlock(0xc38e5c2a1107904d, 0)
+	l.AddFinalBlock(0x0102030405060708, 3)
+
+	for i, tt := range []struct {
+		password []byte
+		bucket   LengthRange
+		inferred LengthRange
+	}{
+		{nil, InvalidLength, InvalidLength},
+		{other[:5], InvalidLength, InvalidLength},
+		{other, LengthRange{0, 7}, LengthRange{3, 3}},
+		{concat(other, other), LengthRange{8, 15}, LengthRange{11, 11}},
+		{concat(other, pad), LengthRange{8, 15}, LengthRange{8, 8}},
+		{concat(pad, other, other[:4]), InvalidLength, InvalidLength},
+		{concat(other, make([]byte, 8)), LengthRange{8, 15}, LengthRange{8, 15}},
+	} {
+		if got := PasswordLength(tt.password); got != tt.bucket {
+			t.Errorf("#%d: PasswordLength: %v want %v", i, got, tt.bucket)
+		}
+		if got := l.Length(tt.password); got != tt.inferred {
+			t.Errorf("#%d: Length: %v want %v", i, got, tt.inferred)
+		}
+	}
+	if InvalidLength.Exact() || InvalidLength.String() != "invalid" {
+		t.Errorf("InvalidLength: exact %t, %v", InvalidLength.Exact(), InvalidLength)
+	}
+}
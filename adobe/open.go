@@ -0,0 +1,51 @@
+package adobe
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNoCredFile is returned by OpenCredFile when no filename is given
+// and neither default file exists.
+var ErrNoCredFile = errors.New("cred or users.tar.gz not found")
+
+// OpenCredFile opens a credential dump, which may be either the
+// extracted cred file or the users.tar.gz archive containing it. When
+// filename is empty, cred and then users.tar.gz are tried in the
+// current directory.
+func OpenCredFile(filename string) (io.ReadCloser, error) {
+	if filename == "" {
+		if _, err := os.Stat("cred"); err == nil {
+			filename = "cred"
+		} else if _, err := os.Stat("users.tar.gz"); err == nil {
+			filename = "users.tar.gz"
+		} else {
+			return nil, ErrNoCredFile
+		}
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(filename, ".tar.gz") || strings.HasSuffix(filename, ".tgz") {
+		tr, err := NewUsersTarGZReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &tarFile{tr, f}, nil
+	}
+	return f, nil
+}
+
+type tarFile struct {
+	*tar.Reader
+	f *os.File
+}
+
+func (t *tarFile) Close() error {
+	return t.f.Close()
+}
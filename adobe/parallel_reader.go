@@ -0,0 +1,318 @@
+package adobe
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// ParallelOptions configures a ParallelCredReader.
+type ParallelOptions struct {
+	Workers   int  // parsing goroutines, defaulting to GOMAXPROCS
+	ChunkSize int  // approximate bytes per chunk, defaulting to 4 MiB
+	Unordered bool // deliver creds as soon as their chunk is parsed
+
+	// MaxLines and Quarantine are as in CredReader. Rejected rows are
+	// passed to the Quarantine in order on a single goroutine.
+	MaxLines   int
+	Quarantine *Quarantine
+}
+
+// ParallelCredReader parses a credential dump on multiple goroutines.
+// The input is split into chunks at record terminators, each chunk is
+// parsed by a CredReader, and the results are reassembled so that
+// records, errors, and ParseError record and line numbers are the same
+// as reading sequentially.
+//
+// In unordered mode, creds are delivered as soon as their chunk is
+// parsed, but errors are still delivered only once the number of
+// records preceding them is known.
+type ParallelCredReader struct {
+	items chan credItem
+	done  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+	err   error // sticky terminal error
+}
+
+type credItem struct {
+	cred   *Cred
+	err    error
+	reject []byte // row to quarantine with err
+}
+
+// chunk is a run of whole lines from the dump.
+type chunk struct {
+	index int
+	data  []byte
+	line  int   // number of lines preceding the chunk
+	last  bool  // chunk ends at the end of input
+	err   error // read error following the chunk
+}
+
+// chunkResult is a parsed chunk. Record numbers in errors are relative
+// to the start of the chunk.
+type chunkResult struct {
+	index   int
+	items   []credItem
+	records int
+	stop    bool // the chunk ended the input
+}
+
+// NewParallelCredReader constructs a ParallelCredReader and begins
+// reading from r. Close must be called if reading ends before io.EOF.
+func NewParallelCredReader(r io.Reader, opts *ParallelOptions) *ParallelCredReader {
+	var o ParallelOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = runtime.GOMAXPROCS(-1)
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = 4 << 20
+	}
+
+	pr := &ParallelCredReader{
+		items: make(chan credItem, 1024),
+		done:  make(chan struct{}),
+	}
+	chunks := make(chan chunk, o.Workers)
+	results := make(chan chunkResult, o.Workers)
+	// bound the chunks in flight, since an ordered reader holds parsed
+	// chunks until all preceding chunks are delivered
+	tokens := make(chan struct{}, 2*o.Workers)
+
+	pr.wg.Add(1)
+	go func() {
+		defer pr.wg.Done()
+		defer close(chunks)
+		pr.split(r, o.ChunkSize, chunks, tokens)
+	}()
+
+	var workers sync.WaitGroup
+	for i := 0; i < o.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for c := range chunks {
+				select {
+				case results <- parseChunk(c, &o):
+				case <-pr.done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	pr.wg.Add(1)
+	go func() {
+		defer pr.wg.Done()
+		defer close(pr.items)
+		pr.collect(results, tokens, &o)
+	}()
+	return pr
+}
+
+// Read reads one cred. Like CredReader, it returns io.EOF after the
+// row count line and a *ParseError for malformed records, after which
+// reading may continue.
+func (r *ParallelCredReader) Read() (*Cred, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	it, ok := <-r.items
+	if !ok {
+		r.err = io.EOF
+		return nil, io.EOF
+	}
+	if it.err == io.EOF {
+		r.err = io.EOF
+	}
+	return it.cred, it.err
+}
+
+// Close stops reading and waits for all goroutines to exit.
+func (r *ParallelCredReader) Close() error {
+	r.once.Do(func() { close(r.done) })
+	for range r.items {
+	}
+	r.wg.Wait()
+	if r.err == nil {
+		r.err = errors.New("adobe: read on closed ParallelCredReader")
+	}
+	return nil
+}
+
+// split reads r into chunks that end at a record terminator.
+func (r *ParallelCredReader) split(rd io.Reader, size int, chunks chan<- chunk, tokens chan struct{}) {
+	terminator := []byte("|--\n")
+	var buf []byte
+	line := 0
+	for index := 0; ; index++ {
+		select {
+		case tokens <- struct{}{}:
+		case <-r.done:
+			return
+		}
+
+		// fill the buffer until it contains a terminator past size
+		var err error
+		end := -1
+		for err == nil {
+			if len(buf) >= size {
+				if i := bytes.LastIndex(buf, terminator); i != -1 {
+					end = i + len(terminator)
+					break
+				}
+			}
+			n := len(buf)
+			if cap(buf)-n < size {
+				grown := make([]byte, n, 2*cap(buf)+size)
+				copy(grown, buf)
+				buf = grown
+			}
+			var m int
+			m, err = rd.Read(buf[n:cap(buf)])
+			buf = buf[:n+m]
+		}
+
+		c := chunk{index: index, line: line}
+		if end == -1 {
+			c.data, c.last = buf, true
+			if err != io.EOF {
+				c.err = err
+			}
+		} else {
+			c.data = buf[:end]
+		}
+		buf = append([]byte(nil), buf[len(c.data):]...)
+		line += bytes.Count(c.data, []byte{'\n'})
+
+		select {
+		case chunks <- c:
+		case <-r.done:
+			return
+		}
+		if c.last {
+			return
+		}
+	}
+}
+
+// parseChunk parses the records in a chunk.
+func parseChunk(c chunk, o *ParallelOptions) chunkResult {
+	res := chunkResult{index: c.index}
+	cr := NewCredReader(bytes.NewReader(c.data))
+	cr.line = c.line
+	cr.deferTotal = true
+	cr.MaxLines = o.MaxLines
+	if o.Quarantine != nil {
+		// hold rejected rows until they can be numbered
+		cr.Quarantine = &Quarantine{hold: true}
+	}
+	for {
+		cred := new(Cred)
+		err := cr.ReadInto(cred)
+		if q := cr.Quarantine; q != nil && len(q.held) != 0 {
+			for _, rej := range q.held {
+				res.items = append(res.items, credItem{err: rej.err, reject: rej.row})
+			}
+			q.held = q.held[:0]
+		}
+		if err == nil {
+			res.items = append(res.items, credItem{cred: cred})
+			continue
+		}
+		if perr, ok := err.(*ParseError); ok && perr.Err == io.EOF {
+			if !c.last {
+				// end of chunk
+				break
+			}
+			if c.err != nil {
+				perr.Err = c.err
+			}
+			res.items = append(res.items, credItem{err: perr})
+			res.stop = true
+			break
+		}
+		res.items = append(res.items, credItem{err: err})
+		if _, ok := err.(*rowCount); ok {
+			res.stop = true
+			break
+		}
+	}
+	res.records = cr.record
+	return res
+}
+
+// collect reassembles parsed chunks, renumbering records in errors.
+func (r *ParallelCredReader) collect(results <-chan chunkResult, tokens <-chan struct{}, o *ParallelOptions) {
+	pending := make(map[int]chunkResult)
+	next, offset := 0, 0
+	send := func(it credItem) bool {
+		select {
+		case r.items <- it:
+			return true
+		case <-r.done:
+			return false
+		}
+	}
+	for res := range results {
+		if o.Unordered {
+			// deliver creds now and hold errors until numbered
+			var errs []credItem
+			for _, it := range res.items {
+				if it.err == nil {
+					if !send(it) {
+						return
+					}
+				} else {
+					errs = append(errs, it)
+				}
+			}
+			res.items = errs
+		}
+		pending[res.index] = res
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			for _, it := range res.items {
+				switch err := it.err.(type) {
+				case *ParseError:
+					if err.Record != -1 {
+						err.Record += offset
+					}
+				case *rowCount:
+					it.err = checkRowCount(err.records, offset+err.parsed, err.line)
+				}
+				if it.reject != nil {
+					qerr := o.Quarantine.Reject(it.err.(*ParseError), it.reject)
+					if qerr == nil {
+						continue
+					}
+					it = credItem{err: qerr}
+				}
+				if !send(it) {
+					return
+				}
+			}
+			if res.stop {
+				r.once.Do(func() { close(r.done) })
+				return
+			}
+			offset += res.records
+			next++
+			<-tokens
+		}
+	}
+}
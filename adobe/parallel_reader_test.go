@@ -0,0 +1,187 @@
+package adobe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var parallelTests = []string{
+	testDump(200, "200 rows selected.\n"),
+	testDump(200, "199 rows selected.\n"),
+	testDump(200, ""),
+	testDump(200, "partial line"),
+	"",
+	"\n\n1-|-a-|-b-|-AAAAAAAAAAA=-|-h|--\n1 rows selected.\n",
+}
+
+// testDump generates a dump with n records, some of which are malformed.
+func testDump(n int, trailer string) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		switch i % 17 {
+		case 3:
+			b.WriteString("\n")
+		case 5:
+			// row split over two lines
+			fmt.Fprintf(&b, "%d-|-user%d-|-user%d@example.com-|-\n", i, i, i)
+			fmt.Fprintf(&b, "AAAAAAAAAAA=-|-hint %d|--\n", i)
+			continue
+		case 7:
+			fmt.Fprintf(&b, "x%d-|-user-|-email-|-AAAAAAAAAAA=-|-bad uid|--\n", i)
+			continue
+		case 11:
+			fmt.Fprintf(&b, "%d-|-unterminated\nrow\n", i)
+			continue
+		case 13:
+			fmt.Fprintf(&b, "%d-|-few-|-columns|--\n", i)
+			continue
+		}
+		fmt.Fprintf(&b, "%d-|-user%d-|-user%d@example.com-|-AAAAAAAAAAA=-|-hint %d|--\n", i, i, i, i)
+	}
+	b.WriteString(trailer)
+	return b.String()
+}
+
+// readSequential reads dump with a CredReader configured like a
+// ParallelCredReader with opts.
+func readSequential(dump string, opts *ParallelOptions) []string {
+	var items []string
+	cr := NewCredReader(strings.NewReader(dump))
+	if opts != nil {
+		cr.MaxLines = opts.MaxLines
+		cr.Quarantine = opts.Quarantine
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			items = append(items, "error: "+err.Error())
+			if perr, ok := err.(*ParseError); ok && (perr.Err == io.EOF || perr.Record == -1) {
+				break
+			}
+			continue
+		}
+		cred, err := ParseRecord(record)
+		if err != nil {
+			items = append(items, "error: "+(&ParseError{cr.record - 1, cr.start, cr.line, err}).Error())
+			continue
+		}
+		items = append(items, fmt.Sprint(*cred))
+	}
+	return items
+}
+
+func readParallel(dump string, opts *ParallelOptions) []string {
+	var items []string
+	pr := NewParallelCredReader(strings.NewReader(dump), opts)
+	defer pr.Close()
+	for {
+		cred, err := pr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			items = append(items, "error: "+err.Error())
+			continue
+		}
+		items = append(items, fmt.Sprint(*cred))
+	}
+	return items
+}
+
+func TestParallelCredReader(t *testing.T) {
+	for i, dump := range parallelTests {
+		want := readSequential(dump, nil)
+		for _, workers := range []int{1, 4} {
+			for _, size := range []int{1, 50, 1000, 1 << 20} {
+				opts := &ParallelOptions{Workers: workers, ChunkSize: size}
+				got := readParallel(dump, opts)
+				if !equalItems(got, want) {
+					t.Errorf("#%d: workers %d, chunk size %d:\ngot  %q\nwant %q", i, workers, size, got, want)
+				}
+
+				opts.Unordered = true
+				got = readParallel(dump, opts)
+				sort.Strings(got)
+				sorted := append([]string(nil), want...)
+				sort.Strings(sorted)
+				if !equalItems(got, sorted) {
+					t.Errorf("#%d: unordered, workers %d, chunk size %d:\ngot  %q\nwant %q", i, workers, size, got, sorted)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelCredReaderLenient(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(testDump(300, ""))
+	for i := 300; i < 400; i++ {
+		// rows split over i%6+1 lines, some past MaxLines
+		fmt.Fprintf(&b, "%d-|-user%d-|-user%d@example.com-|-AAAAAAAAAAA=-|-hint", i, i, i)
+		for j := 0; j < i%6; j++ {
+			fmt.Fprintf(&b, "\nline %d", j)
+		}
+		b.WriteString("|--\n")
+	}
+	b.WriteString("400 rows selected.\n")
+	dump := b.String()
+
+	for _, maxLines := range []int{0, 3} {
+		var wantRows strings.Builder
+		wantQ := NewQuarantine(&wantRows)
+		want := readSequential(dump, &ParallelOptions{MaxLines: maxLines, Quarantine: wantQ})
+		for _, size := range []int{1, 50, 1000} {
+			for _, unordered := range []bool{false, true} {
+				var gotRows strings.Builder
+				gotQ := NewQuarantine(&gotRows)
+				opts := &ParallelOptions{Workers: 4, ChunkSize: size, Unordered: unordered, MaxLines: maxLines, Quarantine: gotQ}
+				got := readParallel(dump, opts)
+				if unordered {
+					sort.Strings(got)
+					sorted := append([]string(nil), want...)
+					sort.Strings(sorted)
+					if !equalItems(got, sorted) {
+						t.Errorf("max lines %d, unordered, chunk size %d:\ngot  %q\nwant %q", maxLines, size, got, sorted)
+					}
+				} else if !equalItems(got, want) {
+					t.Errorf("max lines %d, chunk size %d:\ngot  %q\nwant %q", maxLines, size, got, want)
+				}
+				if gotRows.String() != wantRows.String() {
+					t.Errorf("max lines %d, chunk size %d, unordered %t: quarantined\n%s\nwant\n%s", maxLines, size, unordered, gotRows.String(), wantRows.String())
+				}
+				if gotQ.Total() != wantQ.Total() {
+					t.Errorf("max lines %d, chunk size %d, unordered %t: rejected %d, want %d", maxLines, size, unordered, gotQ.Total(), wantQ.Total())
+				}
+			}
+		}
+	}
+}
+
+func TestParallelCredReaderClose(t *testing.T) {
+	pr := NewParallelCredReader(strings.NewReader(testDump(10000, "")), &ParallelOptions{Workers: 2, ChunkSize: 100})
+	if _, err := pr.Read(); err != nil {
+		t.Fatal(err)
+	}
+	pr.Close()
+	if _, err := pr.Read(); err == nil {
+		t.Error("read after close succeeded")
+	}
+}
+
+func equalItems(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
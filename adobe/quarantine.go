@@ -0,0 +1,90 @@
+package adobe
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Quarantine collects the malformed rows skipped by a lenient
+// CredReader and tallies them by reason.
+type Quarantine struct {
+	w       io.Writer
+	reasons map[string]int
+	total   int
+	held    []rejectedRow // rejected rows held for a ParallelCredReader
+	hold    bool
+}
+
+type rejectedRow struct {
+	err *ParseError
+	row []byte
+}
+
+// RejectCount is the number of rows rejected for a reason.
+type RejectCount struct {
+	Reason string
+	Count  int
+}
+
+// NewQuarantine constructs a Quarantine that writes each rejected row
+// to w, which may be nil, as the ParseError and the quoted row,
+// separated by a tab.
+func NewQuarantine(w io.Writer) *Quarantine {
+	return &Quarantine{w: w, reasons: make(map[string]int)}
+}
+
+// Reject records a malformed row.
+func (q *Quarantine) Reject(err *ParseError, row []byte) error {
+	if q.hold {
+		q.held = append(q.held, rejectedRow{err, append([]byte(nil), row...)})
+		return nil
+	}
+	q.total++
+	q.reasons[rejectReason(err.Err)]++
+	if q.w == nil {
+		return nil
+	}
+	_, werr := fmt.Fprintf(q.w, "%v\t%q\n", err, row)
+	return werr
+}
+
+// Total returns the number of rows rejected.
+func (q *Quarantine) Total() int {
+	return q.total
+}
+
+// Reasons returns the number of rows rejected for each reason, most
+// frequent first.
+func (q *Quarantine) Reasons() []RejectCount {
+	counts := make([]RejectCount, 0, len(q.reasons))
+	for reason, n := range q.reasons {
+		counts = append(counts, RejectCount{reason, n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Reason < counts[j].Reason
+	})
+	return counts
+}
+
+func rejectReason(err error) string {
+	var numErr *strconv.NumError
+	var base64Err base64.CorruptInputError
+	switch {
+	case errors.Is(err, errUnterminated):
+		return "unterminated row"
+	case errors.As(err, new(columnsError)):
+		return "too few columns"
+	case errors.As(err, &numErr):
+		return "invalid uid"
+	case errors.As(err, &base64Err):
+		return "invalid password"
+	}
+	return err.Error()
+}
@@ -0,0 +1,121 @@
+package adobe
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+const lenientDump = `0-|-user0-|-a@example.com-|-AAAAAAAAAAA=-|-hint|--
+x1-|-user1-|-b@example.com-|-AAAAAAAAAAA=-|-bad uid|--
+2-|-user2
+continued
+-|-c@example.com-|-AAAAAAAAAAA=-|-three lines|--
+3-|-user3-|-d@example.com-|-AAAAAAAAAAA=-|-hint|--
+4-|-few columns|--
+5-|-user5-|-e@example.com-|-!!!-|-bad password|--
+6 rows selected.
+`
+
+func TestLenientRead(t *testing.T) {
+	var out strings.Builder
+	q := NewQuarantine(&out)
+	cr := NewCredReader(strings.NewReader(lenientDump))
+	cr.Quarantine = q
+	cr.MaxLines = 2
+	var uids []string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		uids = append(uids, record[0])
+	}
+	if len(uids) != 2 || uids[0] != "0" || uids[1] != "3" {
+		t.Errorf("uids: %q want [0 3]", uids)
+	}
+
+	if q.Total() != 4 {
+		t.Errorf("rejected %d rows, want 4", q.Total())
+	}
+	for _, r := range q.Reasons() {
+		if r.Count != 1 {
+			t.Errorf("reason %q: count %d want 1", r.Reason, r.Count)
+		}
+	}
+	want := `record 1 on line 2: strconv.ParseInt: parsing "x1": invalid syntax	"x1-|-user1-|-b@example.com-|-AAAAAAAAAAA=-|-bad uid"
+record 2 on lines 3-4: unterminated row	"2-|-user2\ncontinued\n-|-c@example.com-|-AAAAAAAAAAA=-|-three lines|--"
+record 4 on line 7: only 2 columns	"4-|-few columns"
+record 5 on line 8: illegal base64 data at input byte 0	"5-|-user5-|-e@example.com-|-!!!-|-bad password"
+`
+	if out.String() != want {
+		t.Errorf("quarantine:\ngot  %q\nwant %q", out.String(), want)
+	}
+}
+
+func TestLenientReadInto(t *testing.T) {
+	q := NewQuarantine(nil)
+	cr := NewCredReader(strings.NewReader(lenientDump))
+	cr.Quarantine = q
+	cr.MaxLines = 2
+	var c Cred
+	var uids []int32
+	for {
+		err := cr.ReadInto(&c)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		uids = append(uids, c.UID)
+	}
+	if len(uids) != 2 || uids[0] != 0 || uids[1] != 3 {
+		t.Errorf("uids: %v want [0 3]", uids)
+	}
+	if q.Total() != 4 {
+		t.Errorf("rejected %d rows, want 4", q.Total())
+	}
+}
+
+func TestLenientResyncRowCount(t *testing.T) {
+	cr := NewCredReader(strings.NewReader("0-|-unterminated\nrow\nmore\n1 rows selected.\n"))
+	cr.Quarantine = NewQuarantine(nil)
+	if _, err := cr.Read(); err != io.EOF {
+		t.Errorf("result: %v want EOF", err)
+	}
+}
+
+func TestLenientResyncNextRecord(t *testing.T) {
+	dump := "0-|-unterminated\n" +
+		"1-|-user1-|-a@example.com-|-AAAAAAAAAAA=-|-hint|--\n" +
+		"2-|-unterminated\nrow\n" +
+		"3-|-user3-|-b@example.com-|-AAAAAAAAAAA=-|-hint|--\n" +
+		"4 rows selected.\n"
+	var out strings.Builder
+	cr := NewCredReader(strings.NewReader(dump))
+	cr.Quarantine = NewQuarantine(&out)
+	var uids []string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		uids = append(uids, record[0])
+	}
+	if len(uids) != 2 || uids[0] != "1" || uids[1] != "3" {
+		t.Errorf("uids: %q want [1 3]", uids)
+	}
+	want := `record 0 on line 1: unterminated row	"0-|-unterminated"
+record 2 on lines 3-4: unterminated row	"2-|-unterminated\nrow"
+`
+	if out.String() != want {
+		t.Errorf("quarantine:\ngot  %q\nwant %q", out.String(), want)
+	}
+}
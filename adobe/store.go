@@ -0,0 +1,415 @@
+package adobe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// A store is a directory holding parsed credentials in columns, with
+// sorted indexes for lookup by uid, email, and password. All integers
+// are little endian.
+//
+//	uid.col                     int32 per row
+//	<column>.dat, <column>.off  concatenated values and n+1 uint64
+//	                            offsets, for username, email,
+//	                            password, and hint
+//	<key>.idx                   uint32 rows sorted by key and then
+//	                            row, for uid, email, and password
+//
+// Lookups binary search an index, reading only the probed values.
+
+const uidColumn = "uid"
+
+// Variable-length columns
+const (
+	usernameColumn = iota
+	emailColumn
+	passwordColumn
+	hintColumn
+)
+
+var varColumns = [...]string{
+	usernameColumn: "username",
+	emailColumn:    "email",
+	passwordColumn: "password",
+	hintColumn:     "hint",
+}
+
+// StoreWriter converts credentials into a store.
+type StoreWriter struct {
+	dir   string
+	files []*os.File
+	uid   *bufio.Writer
+	dat   [len(varColumns)]*bufio.Writer
+	off   [len(varColumns)]*bufio.Writer
+	pos   [len(varColumns)]uint64
+	n     int
+}
+
+// CreateStore creates a store in dir, which is created if needed.
+func CreateStore(dir string) (*StoreWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	w := &StoreWriter{dir: dir}
+	create := func(name string) (*bufio.Writer, error) {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		w.files = append(w.files, f)
+		return bufio.NewWriter(f), nil
+	}
+	var err error
+	if w.uid, err = create(uidColumn + ".col"); err != nil {
+		w.closeFiles()
+		return nil, err
+	}
+	for i, name := range varColumns {
+		if w.dat[i], err = create(name + ".dat"); err != nil {
+			w.closeFiles()
+			return nil, err
+		}
+		if w.off[i], err = create(name + ".off"); err != nil {
+			w.closeFiles()
+			return nil, err
+		}
+		if err := writeUint64(w.off[i], 0); err != nil {
+			w.closeFiles()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Add appends a credential to the store.
+func (w *StoreWriter) Add(c *Cred) error {
+	if uint64(w.n) >= 1<<32-1 {
+		return errors.New("adobe: store full")
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(c.UID))
+	if _, err := w.uid.Write(b[:]); err != nil {
+		return err
+	}
+	values := [len(varColumns)][]byte{[]byte(c.Username), []byte(c.Email), c.Password, []byte(c.Hint)}
+	for i, v := range values {
+		if _, err := w.dat[i].Write(v); err != nil {
+			return err
+		}
+		w.pos[i] += uint64(len(v))
+		if err := writeUint64(w.off[i], w.pos[i]); err != nil {
+			return err
+		}
+	}
+	w.n++
+	return nil
+}
+
+// Close flushes the columns and builds the indexes.
+func (w *StoreWriter) Close() error {
+	bufs := append([]*bufio.Writer{w.uid}, append(w.dat[:], w.off[:]...)...)
+	for _, b := range bufs {
+		if err := b.Flush(); err != nil {
+			w.closeFiles()
+			return err
+		}
+	}
+	if err := w.closeFiles(); err != nil {
+		return err
+	}
+
+	uids, err := os.ReadFile(filepath.Join(w.dir, uidColumn+".col"))
+	if err != nil {
+		return err
+	}
+	if err := writeIndex(w.dir, uidColumn, w.n, func(a, b int) int {
+		ua := int32(binary.LittleEndian.Uint32(uids[4*a:]))
+		ub := int32(binary.LittleEndian.Uint32(uids[4*b:]))
+		switch {
+		case ua < ub:
+			return -1
+		case ua > ub:
+			return 1
+		}
+		return 0
+	}); err != nil {
+		return err
+	}
+	uids = nil
+
+	for _, col := range []int{emailColumn, passwordColumn} {
+		name := varColumns[col]
+		dat, err := os.ReadFile(filepath.Join(w.dir, name+".dat"))
+		if err != nil {
+			return err
+		}
+		offBytes, err := os.ReadFile(filepath.Join(w.dir, name+".off"))
+		if err != nil {
+			return err
+		}
+		off := func(row int) uint64 { return binary.LittleEndian.Uint64(offBytes[8*row:]) }
+		value := func(row int) []byte { return dat[off(row):off(row+1)] }
+		if err := writeIndex(w.dir, name, w.n, func(a, b int) int {
+			return bytes.Compare(value(a), value(b))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *StoreWriter) closeFiles() error {
+	var err error
+	for _, f := range w.files {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	w.files = nil
+	return err
+}
+
+// writeIndex writes the rows sorted by compare and then by row.
+func writeIndex(dir, name string, n int, compare func(a, b int) int) error {
+	rows := make([]uint32, n)
+	for i := range rows {
+		rows[i] = uint32(i)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if c := compare(int(rows[i]), int(rows[j])); c != 0 {
+			return c < 0
+		}
+		return rows[i] < rows[j]
+	})
+	f, err := os.Create(filepath.Join(dir, name+".idx"))
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	var b [4]byte
+	for _, row := range rows {
+		binary.LittleEndian.PutUint32(b[:], row)
+		if _, err := bw.Write(b[:]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeUint64(w io.Writer, x uint64) error {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	_, err := w.Write(b[:])
+	return err
+}
+
+// Store provides random access to credentials in a store.
+type Store struct {
+	n     int
+	files []*os.File
+	uid   *os.File
+	cols  [len(varColumns)]storeColumn
+	idx   map[string]*os.File
+}
+
+type storeColumn struct {
+	dat, off *os.File
+}
+
+// OpenStore opens a store created by StoreWriter.
+func OpenStore(dir string) (*Store, error) {
+	s := &Store{idx: make(map[string]*os.File)}
+	open := func(name string) (*os.File, error) {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		s.files = append(s.files, f)
+		return f, nil
+	}
+	var err error
+	if s.uid, err = open(uidColumn + ".col"); err != nil {
+		s.Close()
+		return nil, err
+	}
+	fi, err := s.uid.Stat()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.n = int(fi.Size() / 4)
+	for i, name := range varColumns {
+		if s.cols[i].dat, err = open(name + ".dat"); err != nil {
+			s.Close()
+			return nil, err
+		}
+		if s.cols[i].off, err = open(name + ".off"); err != nil {
+			s.Close()
+			return nil, err
+		}
+		if fi, err := s.cols[i].off.Stat(); err != nil || fi.Size() != 8*int64(s.n+1) {
+			s.Close()
+			if err == nil {
+				err = fmt.Errorf("adobe: store %s: %s.off has %d bytes, want %d", dir, name, fi.Size(), 8*(s.n+1))
+			}
+			return nil, err
+		}
+	}
+	for _, name := range []string{uidColumn, varColumns[emailColumn], varColumns[passwordColumn]} {
+		f, err := open(name + ".idx")
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.idx[name] = f
+	}
+	return s, nil
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	var err error
+	for _, f := range s.files {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	s.files = nil
+	return err
+}
+
+// Len returns the number of credentials in the store.
+func (s *Store) Len() int {
+	return s.n
+}
+
+// Get reads the credential at a row.
+func (s *Store) Get(row int) (*Cred, error) {
+	if row < 0 || row >= s.n {
+		return nil, fmt.Errorf("adobe: row %d out of range [0, %d)", row, s.n)
+	}
+	uid, err := s.getUID(row)
+	if err != nil {
+		return nil, err
+	}
+	var values [len(varColumns)][]byte
+	for i := range s.cols {
+		if values[i], err = s.cols[i].get(row); err != nil {
+			return nil, err
+		}
+	}
+	return &Cred{
+		uid,
+		string(values[usernameColumn]),
+		string(values[emailColumn]),
+		values[passwordColumn],
+		string(values[hintColumn]),
+	}, nil
+}
+
+// FindUID returns the rows with the given uid.
+func (s *Store) FindUID(uid int32) ([]int, error) {
+	return s.find(uidColumn, func(row int) (int, error) {
+		u, err := s.getUID(row)
+		switch {
+		case err != nil:
+			return 0, err
+		case u < uid:
+			return -1, nil
+		case u > uid:
+			return 1, nil
+		}
+		return 0, nil
+	})
+}
+
+// FindEmail returns the rows with the given email.
+func (s *Store) FindEmail(email string) ([]int, error) {
+	return s.findBytes(emailColumn, []byte(email))
+}
+
+// FindPassword returns the rows with the given encrypted password.
+func (s *Store) FindPassword(password []byte) ([]int, error) {
+	return s.findBytes(passwordColumn, password)
+}
+
+func (s *Store) findBytes(col int, key []byte) ([]int, error) {
+	return s.find(varColumns[col], func(row int) (int, error) {
+		v, err := s.cols[col].get(row)
+		if err != nil {
+			return 0, err
+		}
+		return bytes.Compare(v, key), nil
+	})
+}
+
+// find binary searches an index for the rows whose key compares equal.
+// compare returns the order of the key of a row relative to the
+// searched key.
+func (s *Store) find(name string, compare func(row int) (int, error)) ([]int, error) {
+	idx := s.idx[name]
+	var err error
+	rowAt := func(i int) int {
+		var b [4]byte
+		if _, rerr := idx.ReadAt(b[:], 4*int64(i)); rerr != nil && err == nil {
+			err = rerr
+		}
+		return int(binary.LittleEndian.Uint32(b[:]))
+	}
+	cmpAt := func(i int) int {
+		c, cerr := compare(rowAt(i))
+		if cerr != nil && err == nil {
+			err = cerr
+		}
+		return c
+	}
+	lo := sort.Search(s.n, func(i int) bool { return err != nil || cmpAt(i) >= 0 })
+	var rows []int
+	for i := lo; i < s.n && err == nil; i++ {
+		row := rowAt(i)
+		if c, cerr := compare(row); cerr != nil {
+			err = cerr
+		} else if c != 0 {
+			break
+		}
+		rows = append(rows, row)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *Store) getUID(row int) (int32, error) {
+	var b [4]byte
+	if _, err := s.uid.ReadAt(b[:], 4*int64(row)); err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(b[:])), nil
+}
+
+func (c *storeColumn) get(row int) ([]byte, error) {
+	var b [16]byte
+	if _, err := c.off.ReadAt(b[:], 8*int64(row)); err != nil {
+		return nil, err
+	}
+	start, end := binary.LittleEndian.Uint64(b[:]), binary.LittleEndian.Uint64(b[8:])
+	v := make([]byte, end-start)
+	if _, err := c.dat.ReadAt(v, int64(start)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
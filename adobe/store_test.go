@@ -0,0 +1,81 @@
+package adobe
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	var creds []Cred
+	for i := 0; i < 100; i++ {
+		creds = append(creds, Cred{
+			UID:      int32(1000 - i%50),
+			Username: fmt.Sprintf("user%d", i),
+			Email:    fmt.Sprintf("user%d@example.com", i%30),
+			Password: []byte{byte(i % 7), 1, 2, 3, 4, 5, 6, 7},
+			Hint:     fmt.Sprintf("hint %d", i),
+		})
+	}
+	creds = append(creds, Cred{UID: -1})
+
+	dir := t.TempDir()
+	w, err := CreateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range creds {
+		if err := w.Add(&creds[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := OpenStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Len() != len(creds) {
+		t.Fatalf("Len: %d want %d", s.Len(), len(creds))
+	}
+	for i, want := range creds {
+		c, err := s.Get(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(*c) != fmt.Sprint(want) {
+			t.Errorf("Get(%d): %v want %v", i, *c, want)
+		}
+	}
+
+	check := func(what string, rows []int, err error, match func(c *Cred) bool) {
+		if err != nil {
+			t.Errorf("%s: %v", what, err)
+			return
+		}
+		var want []int
+		for i := range creds {
+			if match(&creds[i]) {
+				want = append(want, i)
+			}
+		}
+		if fmt.Sprint(rows) != fmt.Sprint(want) {
+			t.Errorf("%s: %v want %v", what, rows, want)
+		}
+	}
+	for _, uid := range []int32{1000, 951, 999, 950, -1, 0, 2000} {
+		rows, err := s.FindUID(uid)
+		check(fmt.Sprintf("FindUID(%d)", uid), rows, err, func(c *Cred) bool { return c.UID == uid })
+	}
+	for _, email := range []string{"user0@example.com", "user29@example.com", "", "nobody"} {
+		rows, err := s.FindEmail(email)
+		check(fmt.Sprintf("FindEmail(%q)", email), rows, err, func(c *Cred) bool { return c.Email == email })
+	}
+	for i := 0; i < 8; i++ {
+		pw := []byte{byte(i), 1, 2, 3, 4, 5, 6, 7}
+		rows, err := s.FindPassword(pw)
+		check(fmt.Sprintf("FindPassword(%x)", pw), rows, err, func(c *Cred) bool { return string(c.Password) == string(pw) })
+	}
+}
@@ -0,0 +1,112 @@
+package adobe
+
+import "strings"
+
+// KeyReport summarizes the passwords decrypted with a candidate key.
+// With a wrong key, a password rarely has valid padding and almost
+// never decrypts to printable text, so a false positive from a single
+// known pair is quickly revealed.
+type KeyReport struct {
+	Passwords   int // passwords decrypted
+	Padded      int // passwords with valid padding
+	Printable   int // padded passwords of only printable ASCII
+	Hinted      int // printable passwords with a hint
+	HintMatches int // hinted passwords that match a guess from the hint
+}
+
+// Confidence returns the fraction of passwords that decrypt to
+// printable text with valid padding, scaled by agreement with hints. A
+// correct key decrypts some passwords to a guess from their hint and a
+// wrong key almost none, so once enough printable passwords have hints,
+// the score is reduced in proportion when fewer than the expected share
+// of them match.
+func (r *KeyReport) Confidence() float64 {
+	if r.Passwords == 0 {
+		return 0
+	}
+	c := float64(r.Printable) / float64(r.Passwords)
+	if r.Hinted >= minHinted {
+		if f := r.HintRate() / expectedHintRate; f < 1 {
+			c *= f
+		}
+	}
+	return c
+}
+
+const (
+	// minHinted is the fewest hinted passwords needed to judge a key
+	// by its hint agreement.
+	minHinted = 20
+
+	// expectedHintRate is a conservative share of hinted passwords that
+	// match a guess from their hint when decrypted with the correct
+	// key.
+	expectedHintRate = 0.05
+)
+
+// HintRate returns the fraction of printable passwords with a hint
+// that match a guess from the hint.
+func (r *KeyReport) HintRate() float64 {
+	if r.Hinted == 0 {
+		return 0
+	}
+	return float64(r.HintMatches) / float64(r.Hinted)
+}
+
+// KeyVerifier checks a candidate key against encrypted passwords.
+type KeyVerifier struct {
+	c      BlockCipher
+	p      Padding
+	report KeyReport
+}
+
+// NewKeyVerifier constructs a KeyVerifier for a cipher with a
+// candidate key.
+func NewKeyVerifier(c BlockCipher, p Padding) *KeyVerifier {
+	return &KeyVerifier{c: c, p: p}
+}
+
+// Add decrypts the password of a credential and returns the plaintext
+// when it is printable with valid padding.
+func (v *KeyVerifier) Add(c *Cred) ([]byte, bool) {
+	if len(c.Password) == 0 || len(c.Password)%8 != 0 {
+		return nil, false
+	}
+	r := &v.report
+	r.Passwords++
+	plain, err := DecryptPassword(v.c, c.Password, v.p)
+	if err != nil {
+		return nil, false
+	}
+	r.Padded++
+	if !printable(plain) {
+		return nil, false
+	}
+	r.Printable++
+	if c.Hint != "" {
+		r.Hinted++
+		if HintMatches(c, string(plain)) {
+			r.HintMatches++
+		}
+	}
+	return plain, true
+}
+
+// Report returns the summary of the passwords added so far.
+func (v *KeyVerifier) Report() KeyReport {
+	return v.report
+}
+
+// HintMatches reports whether a plaintext password is suggested by the
+// hint of a credential: it matches a guess from the hint or from the
+// account it refers to, ignoring case, or, when at least four
+// characters, appears within the hint.
+func HintMatches(c *Cred, password string) bool {
+	hint := NormalizeHint(c.Hint)
+	for _, guess := range append(HintGuesses(hint), AccountGuesses(c)...) {
+		if strings.EqualFold(guess, password) {
+			return true
+		}
+	}
+	return len(password) >= 4 && strings.Contains(hint, strings.ToLower(password))
+}
@@ -0,0 +1,75 @@
+package adobe
+
+import (
+	"testing"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+func TestKeyVerifier(t *testing.T) {
+	c := des.NewCipher(0x0123456789abcdef)
+	creds := []*Cred{
+		{Password: EncryptPassword(c, []byte("fido1"), PKCS5Padding), Hint: "my dog"},
+		{Password: EncryptPassword(c, []byte("fido123"), PKCS5Padding), Hint: "dog fido"},
+		{Password: EncryptPassword(c, []byte("Summer2013"), PKCS5Padding), Hint: "summer"},
+		{Password: EncryptPassword(c, []byte("letmein"), PKCS5Padding)},
+	}
+
+	v := NewKeyVerifier(c, PKCS5Padding)
+	for _, cred := range creds {
+		if _, ok := v.Add(cred); !ok {
+			t.Errorf("password not printable: %x", cred.Password)
+		}
+	}
+	want := KeyReport{Passwords: 4, Padded: 4, Printable: 4, Hinted: 3, HintMatches: 2}
+	if r := v.Report(); r != want {
+		t.Errorf("report %+v want %+v", r, want)
+	} else if r.Confidence() != 1 || r.HintRate() != 2.0/3 {
+		t.Errorf("confidence %v hint rate %v", r.Confidence(), r.HintRate())
+	}
+
+	wrong := NewKeyVerifier(des.NewCipher(0x23456789abcdef01), PKCS5Padding)
+	for _, cred := range creds {
+		wrong.Add(cred)
+	}
+	if r := wrong.Report(); r.Printable != 0 || r.Confidence() != 0 {
+		t.Errorf("wrong key: %+v", r)
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	for _, tt := range []struct {
+		r    KeyReport
+		want float64
+	}{
+		{KeyReport{}, 0},
+		{KeyReport{Passwords: 100, Printable: 90, Hinted: 10}, 0.9},
+		{KeyReport{Passwords: 100, Printable: 100, Hinted: 40, HintMatches: 2}, 1},
+		{KeyReport{Passwords: 100, Printable: 100, Hinted: 40, HintMatches: 1}, 0.5},
+		{KeyReport{Passwords: 100, Printable: 100, Hinted: 40}, 0},
+	} {
+		if c := tt.r.Confidence(); c != tt.want {
+			t.Errorf("%+v: confidence %v want %v", tt.r, c, tt.want)
+		}
+	}
+}
+
+func TestHintMatches(t *testing.T) {
+	tests := []struct {
+		hint, password string
+		match          bool
+	}{
+		{"same as my dog fido", "Fido1", true},
+		{"Rhymes with cat!", "hat", true},
+		{"password is hunter2", "hunter2", true},
+		{"same as email", "Bob123", true},
+		{"favorite color", "blue", false},
+		{"abc", "ab", false},
+	}
+	for _, tt := range tests {
+		c := &Cred{Username: "", Email: "bob@example.com", Hint: tt.hint}
+		if match := HintMatches(c, tt.password); match != tt.match {
+			t.Errorf("HintMatches(%q, %q) = %t", tt.hint, tt.password, match)
+		}
+	}
+}
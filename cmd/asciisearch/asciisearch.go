@@ -1,61 +1,202 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/binary"
+	"flag"
 	"log"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/andrewarchi/adobe-cred/des"
+	"github.com/andrewarchi/adobe-cred/internal/block"
 )
 
 var (
 	cipher uint64 = 0x2fca9b003de39778
-	plain  uint64 = binary.BigEndian.Uint64([]byte("password"))
+	plain  uint64 = block.Password
 
-	count = 0
-	t0    = time.Now()
+	mask        string
+	plainText   string
+	cipherText  string
+	passwordKey bool
 )
 
+// DES search of keys built from a mask of characters per key byte.
 func main() {
-	t0 := time.Now()
-	c := des.NewCracker(plain, cipher)
-	if key, ok := searchASCIIKey(c, 0, 0); ok {
-		log.Printf("Key %d found in %v", key, time.Since(t0))
+	flag.Var((*block.Hex)(&plain), "plain", "plaintext block in hex")
+	flag.Var((*block.Hex)(&cipher), "cipher", "cipher text block in hex")
+	flag.StringVar(&plainText, "plaintext", "", "plaintext block as 8 characters, overriding -plain")
+	flag.StringVar(&cipherText, "ciphertext", "", "base64 encrypted password whose first block is the cipher text, overriding -cipher")
+	flag.StringVar(&mask, "mask", "?a?a?a?a?a?a?a?a", "charset of each key byte, such as ?l?l?d")
+	flag.BoolVar(&passwordKey, "passwordkey", false, "treat the key as a password of up to 8 characters, null padded")
+	flag.Parse()
+
+	if plainText != "" {
+		if len(plainText) != 8 {
+			log.Fatalf("plaintext %q is not 8 characters", plainText)
+		}
+		plain = binary.BigEndian.Uint64([]byte(plainText))
+	}
+	if cipherText != "" {
+		b, err := base64.StdEncoding.DecodeString(cipherText)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(b) < 8 {
+			log.Fatalf("cipher text %q is shorter than a block", cipherText)
+		}
+		cipher = binary.BigEndian.Uint64(b)
+	}
+	positions, err := parseMask(mask)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(positions) > 8 || !passwordKey && len(positions) != 8 {
+		log.Fatalf("mask %q has %d positions, want 8 or up to 8 with -passwordkey", mask, len(positions))
+	}
+	n := len(positions)
+	for len(positions) < 8 {
+		positions = append(positions, "\x00")
+	}
+	table, keys := keyTable(positions)
+
+	log.SetOutput(os.Stdout)
+	workers := runtime.GOMAXPROCS(-1)
+	log.Printf("Searching %d keys with %d workers\n", keys, workers)
+	s := &searcher{c: des.NewCracker(plain, cipher), table: &table, t0: time.Now()}
+	key, ok := s.run(workers)
+	d := time.Since(s.t0)
+	tried := atomic.LoadUint64(&s.count)
+	log.Printf("Tried %d keys in %v, %.0f keys/s\n", tried, d, float64(tried)/d.Seconds())
+	if ok {
+		log.Printf("Key 0x%016x %q found in %v", key, maskKey(positions, key)[:n], d)
+		return
 	}
-	log.Printf("Key not found in %v", time.Since(t0))
+	log.Printf("Key not found in %v", d)
 }
 
-func searchASCIIKey(c *des.Cracker, keyPrefix uint64, i uint8) (uint64, bool) {
-	// Right shift skips parity bits
-	for b := uint64(' ' >> 1); b <= '~'>>1; b++ {
-		k := keyPrefix | (b << i)
-		if i == 49 {
-			if key, ok := c.CheckKey(k); ok {
-				return key, true
+// keyTable computes the permuted key bits for each distinct character
+// of each key byte. DES ignores the low parity bit of each byte, so
+// characters differing only in that bit are the same key. It returns
+// the number of distinct keys.
+func keyTable(positions []string) (table [8][]uint64, keys uint64) {
+	keys = 1
+	for i, charset := range positions {
+		var seen [128]bool
+		for j := 0; j < len(charset); j++ {
+			b := charset[j] >> 1
+			if !seen[b] {
+				seen[b] = true
+				key := uint64(b) << 1 << (56 - 8*i)
+				table[i] = append(table[i], des.PermuteKey(key))
 			}
-			count++
-			if count&0xffffff == 0 {
-				log.Printf("Tried %d keys in %v\n", count, time.Since(t0))
+		}
+		keys *= uint64(len(table[i]))
+	}
+	return table, keys
+}
+
+// maskKey returns the characters of the mask that form a key. Since
+// the parity bits are ignored, the first of the equivalent characters
+// in each charset is chosen.
+func maskKey(positions []string, key uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], key)
+	for i, charset := range positions {
+		for j := 0; j < len(charset); j++ {
+			if charset[j]>>1 == b[i]>>1 {
+				b[i] = charset[j]
+				break
 			}
-			// var s [8]byte
-			// binary.BigEndian.PutUint64(s[:], unpackParity(k))
-			// log.Printf("Tried key %s\n", string(s[:]))
-		} else if key, ok := searchASCIIKey(c, k, i+7); ok {
-			return key, true
 		}
 	}
-	return 0, false
+	return b[:]
+}
+
+// searcher searches the keys of a table on multiple goroutines.
+type searcher struct {
+	c     *des.Cracker
+	table *[8][]uint64
+	t0    time.Time
+	count uint64 // keys tried, updated atomically
+	done  int32  // set atomically when a key is found
+
+	mu    sync.Mutex
+	key   uint64
+	found bool
 }
 
-// unpackParity expands a 56-bit key into 64 bits by interspersing zeros
-// at every eighth bit.
-func unpackParity(key56 uint64) (key64 uint64) {
-	return (key56&0x7f)<<1 |
-		(key56&(0x7f<<7))<<2 |
-		(key56&(0x7f<<14))<<3 |
-		(key56&(0x7f<<21))<<4 |
-		(key56&(0x7f<<28))<<5 |
-		(key56&(0x7f<<35))<<6 |
-		(key56&(0x7f<<42))<<7 |
-		(key56&(0x7f<<49))<<8
+// run partitions the leading key bytes into prefixes, with enough
+// prefixes to balance the workers, and searches them in parallel until
+// a key is found.
+func (s *searcher) run(workers int) (uint64, bool) {
+	prefixes, depth := []uint64{0}, 0
+	for depth < 7 && len(prefixes) < 4*workers {
+		var next []uint64
+		for _, prefix := range prefixes {
+			for _, b := range s.table[depth] {
+				next = append(next, prefix|b)
+			}
+		}
+		prefixes = next
+		depth++
+	}
+
+	work := make(chan uint64)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range work {
+				if key, ok := s.search(prefix, depth); ok {
+					s.mu.Lock()
+					if !s.found {
+						s.key, s.found = key, true
+					}
+					s.mu.Unlock()
+					atomic.StoreInt32(&s.done, 1)
+				}
+			}
+		}()
+	}
+	for _, prefix := range prefixes {
+		if atomic.LoadInt32(&s.done) != 0 {
+			break
+		}
+		work <- prefix
+	}
+	close(work)
+	wg.Wait()
+	return s.key, s.found
+}
+
+// search searches the keys beginning with a prefix of i key bytes.
+func (s *searcher) search(keyPrefix uint64, i int) (uint64, bool) {
+	if atomic.LoadInt32(&s.done) != 0 {
+		return 0, false
+	}
+	if i == 7 {
+		for _, b := range s.table[7] {
+			if key, ok := s.c.CheckKey(keyPrefix | b); ok {
+				return key, true
+			}
+		}
+		n := uint64(len(s.table[7]))
+		if count := atomic.AddUint64(&s.count, n); count&^0xffffff != (count-n)&^0xffffff {
+			d := time.Since(s.t0)
+			log.Printf("Tried %d keys in %v, %.0f keys/s\n", count, d, float64(count)/d.Seconds())
+		}
+		return 0, false
+	}
+	for _, b := range s.table[i] {
+		if key, ok := s.search(keyPrefix|b, i+1); ok {
+			return key, true
+		}
+	}
+	return 0, false
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	lower  = "abcdefghijklmnopqrstuvwxyz"
+	upper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits = "0123456789"
+	// symbols includes space, so that ?a covers ' ' through '~'
+	symbols = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+var maskCharsets = map[byte]string{
+	'l': lower,
+	'u': upper,
+	'd': digits,
+	's': symbols,
+	'a': lower + upper + digits + symbols,
+	'h': digits + "abcdef",
+	'H': digits + "ABCDEF",
+}
+
+// parseMask parses a hashcat-style mask into a charset per position.
+// ?l, ?u, ?d, ?s, ?a, ?h, and ?H are lowercase, uppercase, digits,
+// symbols, all printable ASCII, and lowercase and uppercase hex digits;
+// ?b is any byte, ?? is a literal ?, and any other character is itself.
+func parseMask(mask string) ([]string, error) {
+	var positions []string
+	for i := 0; i < len(mask); i++ {
+		if mask[i] != '?' {
+			positions = append(positions, mask[i:i+1])
+			continue
+		}
+		i++
+		if i == len(mask) {
+			return nil, fmt.Errorf("mask %q: trailing ?", mask)
+		}
+		switch c := mask[i]; c {
+		case '?':
+			positions = append(positions, "?")
+		case 'b':
+			var b strings.Builder
+			for x := 0; x < 256; x++ {
+				b.WriteByte(byte(x))
+			}
+			positions = append(positions, b.String())
+		default:
+			charset, ok := maskCharsets[c]
+			if !ok {
+				return nil, fmt.Errorf("mask %q: unknown charset ?%c", mask, c)
+			}
+			positions = append(positions, charset)
+		}
+	}
+	return positions, nil
+}
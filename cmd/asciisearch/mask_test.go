@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMask(t *testing.T) {
+	tests := []struct {
+		mask string
+		want []string
+		err  bool
+	}{
+		{"", nil, false},
+		{"pass", []string{"p", "a", "s", "s"}, false},
+		{"?l?u?d", []string{lower, upper, digits}, false},
+		{"?s?a", []string{symbols, lower + upper + digits + symbols}, false},
+		{"?h?H", []string{"0123456789abcdef", "0123456789ABCDEF"}, false},
+		{"a??b", []string{"a", "?", "b"}, false},
+		{"x?d", []string{"x", digits}, false},
+		{"?x", nil, true},
+		{"ab?", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseMask(tt.mask)
+		if (err != nil) != tt.err {
+			t.Errorf("parseMask(%q): error %v", tt.mask, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMask(%q) = %q, want %q", tt.mask, got, tt.want)
+		}
+	}
+
+	got, err := parseMask("?b")
+	if err != nil || len(got) != 1 || len(got[0]) != 256 {
+		t.Fatalf("parseMask(\"?b\") = %q, %v", got, err)
+	}
+	for x := 0; x < 256; x++ {
+		if got[0][x] != byte(x) {
+			t.Fatalf("parseMask(\"?b\"): byte %d is %d", x, got[0][x])
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+)
+
+var (
+	blocksFile    string
+	passwordsFile string
+)
+
+// Frequency analysis of ECB password blocks.
+func main() {
+	flag.StringVar(&blocksFile, "blocks", "blocks.tsv", "output file for block counts")
+	flag.StringVar(&passwordsFile, "passwords", "passwords.tsv", "output file for password counts")
+	flag.Parse()
+
+	f, err := adobe.OpenCredFile(flag.Arg(0))
+	try(err)
+	defer f.Close()
+
+	bc := adobe.NewBlockCounter()
+	cr := adobe.NewCredReader(f)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err, record)
+			continue
+		}
+		bc.Add(cred)
+	}
+
+	out, err := os.Create(blocksFile)
+	try(err)
+	try(adobe.WriteBlockCounts(out, bc.Blocks()))
+	try(out.Close())
+
+	out, err = os.Create(passwordsFile)
+	try(err)
+	try(adobe.WritePasswordCounts(out, bc.Passwords()))
+	try(out.Close())
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -1,36 +1,49 @@
 package main
 
 import (
-	"encoding/binary"
 	"flag"
 	"log"
 	"os"
 	"os/signal"
 	"runtime"
 	"runtime/pprof"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/andrewarchi/adobe-cred/des"
+	"github.com/andrewarchi/adobe-cred/internal/block"
 )
 
 var (
 	cipher uint64 = 0x2fca9b003de39778
-	plain  uint64 = binary.BigEndian.Uint64([]byte("password"))
+	plain  uint64 = block.Password
 
-	start      uint64
-	end        uint64
-	step       uint64
-	cpuProfile string
+	start       uint64
+	end         uint64
+	step        uint64
+	cpuProfile  string
+	journalFile string
+	resume      bool
+	bitslice    bool
+	coordAddr   string
+	workerURL   string
+	leaseTTL    time.Duration
 )
 
-// DES brute force of all 56-bit keys.
+// DES brute force of all 56-bit keys, either on this machine or
+// distributed by a coordinator to worker processes.
 func main() {
 	flag.Uint64Var(&start, "start", 0, "starting search bound")
 	flag.Uint64Var(&end, "end", 1<<56, "ending search bound")
 	flag.Uint64Var(&step, "step", 1<<24, "search increment")
 	flag.StringVar(&cpuProfile, "cpuprofile", "", "write cpu profile to file")
+	flag.StringVar(&journalFile, "journal", "bruteforce.journal", "journal of completed ranges")
+	flag.BoolVar(&resume, "resume", false, "resume from an existing journal")
+	flag.BoolVar(&bitslice, "bitslice", true, "search 64 keys at a time with bitsliced DES")
+	flag.StringVar(&coordAddr, "coordinator", "", "lease ranges to workers over HTTP on this address, such as :8056")
+	flag.StringVar(&workerURL, "worker", "", "search ranges leased from the coordinator at this URL, such as http://host:8056")
+	flag.DurationVar(&leaseTTL, "lease", 10*time.Minute, "time before a coordinator reassigns a leased range")
 	flag.Parse()
 
 	if cpuProfile != "" {
@@ -41,7 +54,7 @@ func main() {
 		pprof.StartCPUProfile(f)
 		defer pprof.StopCPUProfile()
 
-		c := make(chan os.Signal)
+		c := make(chan os.Signal, 1)
 		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 		go func() {
 			<-c
@@ -50,16 +63,54 @@ func main() {
 		}()
 	}
 
-	k := start
+	workers := runtime.GOMAXPROCS(-1)
+	log.SetOutput(os.Stdout)
+	if workerURL != "" {
+		runWorker(strings.TrimSuffix(workerURL, "/"), workers)
+		return
+	}
+
+	var (
+		j     *journal
+		found []uint64
+		err   error
+	)
+	sched := &scheduler{next: start, end: end, step: step}
+	if resume {
+		j, sched.done, found, err = resumeJournal(journalFile, start, end, step)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(found) != 0 {
+			for _, key := range found {
+				log.Printf("Found key 0x%x in previous run\n", key)
+			}
+			return
+		}
+	} else {
+		j, err = createJournal(journalFile, start, end, step)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	defer j.Close()
+
+	if coordAddr != "" {
+		log.Printf("Coordinating on %s, %d keys remaining\n", coordAddr, sched.remaining())
+		c := newCoordinator(sched, j, leaseTTL, plain, cipher)
+		if err := c.serve(coordAddr, 10*time.Second); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	done := false
 	var mu sync.Mutex
 
-	workers := runtime.GOMAXPROCS(-1)
-	log.SetOutput(os.Stdout)
-	log.Printf("Searching with %d workers\n", workers)
+	log.Printf("Searching with %d workers, %d keys remaining\n", workers, sched.remaining())
 	t0 := time.Now()
 
-	c := des.NewCracker(plain, cipher)
+	c := newSearcher(plain, cipher)
 
 	var wg sync.WaitGroup
 	for i := 0; i < workers; i++ {
@@ -67,25 +118,33 @@ func main() {
 		go func() {
 			for {
 				mu.Lock()
-				if k == end || done {
+				if done {
 					mu.Unlock()
 					break
 				}
-				min, max := k, k+step
-				k = max
+				min, max, ok := sched.take()
 				mu.Unlock()
+				if !ok {
+					break
+				}
 
 				t := time.Now()
 				if key, ok := c.SearchKey(min, max); ok {
 					log.Printf("Found key 0x%x in %v\n", key, time.Since(t0))
+					if err := j.found(key); err != nil {
+						log.Fatal(err)
+					}
 					mu.Lock()
 					done = true
 					mu.Unlock()
 					break
 				}
+				if err := j.complete(min, max); err != nil {
+					log.Fatal(err)
+				}
 				now := time.Now()
 				d := now.Sub(t)
-				log.Printf("Searched 0x%x to 0x%x in %v, %v/op, %v elapsed\n", min, max, d, d/time.Duration(step), now.Sub(t0))
+				log.Printf("Searched 0x%x to 0x%x in %v, %v/op, %v elapsed\n", min, max, d, d/time.Duration(max-min), now.Sub(t0))
 			}
 
 			wg.Done()
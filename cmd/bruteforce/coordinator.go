@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+// The coordinator leases key ranges to worker processes over HTTP with
+// JSON bodies:
+//
+//	POST /lease     returns a leaseResponse, or 204 No Content when
+//	                all remaining ranges are leased and 410 Gone when
+//	                the search is over
+//	POST /complete  takes a completion for a leased range
+//	GET  /status    returns a statusResponse
+//
+// A lease that is not completed before it expires is reassigned to the
+// next worker to ask. Completions of expired leases are still accepted,
+// since the range was searched either way, but only the range of an
+// outstanding lease is recorded. A completion for a range that has
+// since been completed by another worker is answered with 409 Conflict
+// and other rejected completions with 400 Bad Request, and in either
+// case the worker drops it and leases another range.
+type coordinator struct {
+	mu      sync.Mutex
+	sched   *scheduler
+	j       *journal
+	ttl     time.Duration
+	in, out uint64
+
+	leases  map[uint64]*lease // outstanding leases by id
+	firstID uint64            // ids issued by this run follow firstID
+	nextID  uint64
+	found   []uint64
+	over    bool
+	overCh  chan struct{}
+	started time.Time
+	keys    uint64 // keys completed since start
+}
+
+type lease struct {
+	span
+	expires    time.Time
+	reassigned bool
+}
+
+type leaseResponse struct {
+	ID     uint64 `json:"id"`
+	Min    uint64 `json:"min"`
+	Max    uint64 `json:"max"`
+	Plain  uint64 `json:"plain"`
+	Cipher uint64 `json:"cipher"`
+}
+
+type completion struct {
+	ID    uint64 `json:"id"`
+	Min   uint64 `json:"min"`
+	Max   uint64 `json:"max"`
+	Found bool   `json:"found"`
+	Key   uint64 `json:"key"`
+}
+
+type statusResponse struct {
+	Remaining uint64   `json:"remaining"`
+	Leases    int      `json:"leases"`
+	Found     []uint64 `json:"found"`
+	Over      bool     `json:"over"`
+}
+
+func newCoordinator(sched *scheduler, j *journal, ttl time.Duration, in, out uint64) *coordinator {
+	started := time.Now()
+	// prefix lease ids with the start time, so that completions of
+	// leases from before a restart are not mistaken for new leases
+	firstID := uint64(started.Unix()) << 32
+	return &coordinator{
+		sched:   sched,
+		j:       j,
+		ttl:     ttl,
+		in:      in,
+		out:     out,
+		leases:  make(map[uint64]*lease),
+		firstID: firstID,
+		nextID:  firstID,
+		overCh:  make(chan struct{}),
+		started: started,
+	}
+}
+
+// serve runs the coordinator on addr until the search is over. It
+// continues answering for grace afterwards so that workers learn that
+// the search is over.
+func (c *coordinator) serve(addr string, grace time.Duration) error {
+	srv := &http.Server{Addr: addr, Handler: c.handler()}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	select {
+	case err := <-errc:
+		return err
+	case <-c.overCh:
+	}
+	time.Sleep(grace)
+	return srv.Shutdown(context.Background())
+}
+
+func (c *coordinator) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lease", c.handleLease)
+	mux.HandleFunc("/complete", c.handleComplete)
+	mux.HandleFunc("/status", c.handleStatus)
+	return mux
+}
+
+var (
+	errUnknownLease = errors.New("unknown lease")
+	errStaleLease   = errors.New("lease already completed")
+	errLeaseSpan    = errors.New("completion does not match lease")
+	errBadKey       = errors.New("found key does not encrypt the plaintext")
+)
+
+// lease assigns a range, preferring expired leases, and returns false
+// when no range is available.
+func (c *coordinator) lease(now time.Time) (uint64, span, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.over {
+		return 0, span{}, false
+	}
+	var s span
+	found := false
+	// reassign the earliest expired lease
+	var expired *lease
+	for _, l := range c.leases {
+		if !l.reassigned && now.After(l.expires) && (expired == nil || l.expires.Before(expired.expires)) {
+			expired = l
+		}
+	}
+	if expired != nil {
+		expired.reassigned = true
+		s, found = expired.span, true
+		log.Printf("Lease of 0x%x to 0x%x expired; reassigning\n", s.min, s.max)
+	}
+	if !found {
+		s.min, s.max, found = c.sched.take()
+	}
+	if !found {
+		return 0, span{}, false
+	}
+	c.nextID++
+	c.leases[c.nextID] = &lease{span: s, expires: now.Add(c.ttl)}
+	return c.nextID, s, true
+}
+
+// complete records the range of a lease as searched and ends the
+// search when a key is found or no ranges remain. The completion must
+// match an outstanding lease, so that a stale or faulty worker cannot
+// mark an unsearched range as done.
+func (c *coordinator) complete(r completion) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.leases[r.ID]
+	if !ok {
+		if r.ID > c.firstID && r.ID <= c.nextID {
+			return errStaleLease
+		}
+		return errUnknownLease
+	}
+	s := l.span
+	if s != (span{r.Min, r.Max}) {
+		return errLeaseSpan
+	}
+	if r.Found && des.NewCipher(r.Key).EncryptBlock(c.in) != c.out {
+		return errBadKey
+	}
+
+	if r.Found {
+		if err := c.j.found(r.Key); err != nil {
+			return err
+		}
+	} else if err := c.j.complete(s.min, s.max); err != nil {
+		return err
+	}
+
+	// drop the lease and any reassignments of the same range
+	for id, l := range c.leases {
+		if l.span == s {
+			delete(c.leases, id)
+		}
+	}
+	c.keys += s.max - s.min
+	d := time.Since(c.started)
+	if r.Found {
+		log.Printf("Found key 0x%x in %v\n", r.Key, d)
+		c.found = append(c.found, r.Key)
+		c.end()
+	} else {
+		log.Printf("Searched 0x%x to 0x%x, %d keys remaining, %v/op, %v elapsed\n",
+			s.min, s.max, c.sched.remaining(), d/time.Duration(c.keys), d)
+		if len(c.leases) == 0 && c.sched.remaining() == 0 {
+			log.Printf("Key not found in %v\n", d)
+			c.end()
+		}
+	}
+	return nil
+}
+
+func (c *coordinator) end() {
+	if !c.over {
+		c.over = true
+		close(c.overCh)
+	}
+}
+
+func (c *coordinator) handleLease(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, s, ok := c.lease(time.Now())
+	if !ok {
+		c.mu.Lock()
+		over := c.over
+		c.mu.Unlock()
+		if over {
+			w.WriteHeader(http.StatusGone)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+	writeJSON(w, leaseResponse{id, s.min, s.max, c.in, c.out})
+}
+
+func (c *coordinator) handleComplete(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var r completion
+	if err := json.NewDecoder(req.Body).Decode(&r); err != nil || r.Min >= r.Max {
+		http.Error(w, "bad completion", http.StatusBadRequest)
+		return
+	}
+	switch err := c.complete(r); err {
+	case nil:
+	case errStaleLease:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errUnknownLease, errLeaseSpan, errBadKey:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *coordinator) handleStatus(w http.ResponseWriter, req *http.Request) {
+	c.mu.Lock()
+	s := statusResponse{c.sched.remaining(), len(c.leases), c.found, c.over}
+	c.mu.Unlock()
+	writeJSON(w, s)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+const testKey uint64 = 0x0e329232ea6d0d73
+
+// testSearch returns the bounds of a search of 0x4000 keys around the
+// planted key.
+func testSearch() (start, end, step uint64) {
+	p := des.PermuteKey(testKey) &^ 0xfff
+	return p, p + 0x4000, 0x400
+}
+
+func newTestCoordinator(t *testing.T, ttl time.Duration) (*coordinator, string) {
+	t.Helper()
+	start, end, step := testSearch()
+	sched := &scheduler{next: start, end: end, step: step}
+	name := filepath.Join(t.TempDir(), "journal")
+	j, err := createJournal(name, start, end, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { j.Close() })
+	c := newCoordinator(sched, j, ttl, plain, des.NewCipher(testKey).EncryptBlock(plain))
+	return c, name
+}
+
+func TestCoordinatorWorkers(t *testing.T) {
+	c, name := newTestCoordinator(t, time.Minute)
+	srv := httptest.NewServer(c.handler())
+	defer srv.Close()
+
+	runWorker(srv.URL, 2)
+	c.mu.Lock()
+	found, over := c.found, c.over
+	c.mu.Unlock()
+	if !over || len(found) != 1 || des.PermuteKey(found[0]) != des.PermuteKey(testKey) {
+		t.Fatalf("found %x, over %t", found, over)
+	}
+
+	start, end, step := testSearch()
+	j, _, keys, err := resumeJournal(name, start, end, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+	if len(keys) != 1 || keys[0] != found[0] {
+		t.Errorf("journaled keys %x", keys)
+	}
+}
+
+func TestCoordinatorLeaseExpiry(t *testing.T) {
+	c, _ := newTestCoordinator(t, time.Minute)
+	now := time.Now()
+	id1, s1, ok := c.lease(now)
+	if !ok {
+		t.Fatal("no lease")
+	}
+	id2, s2, _ := c.lease(now.Add(time.Second))
+	if s2 == s1 {
+		t.Fatalf("unexpired lease reassigned: %v", s2)
+	}
+	// only the first lease has expired
+	id3, s3, ok := c.lease(now.Add(time.Minute + time.Millisecond))
+	if !ok || s3 != s1 || id3 == id1 {
+		t.Fatalf("expired lease %d %v reassigned as %d %v", id1, s1, id3, s3)
+	}
+	// both have expired, but the first is already reassigned
+	if id4, s4, ok := c.lease(now.Add(time.Minute + 2*time.Second)); !ok || s4 != s2 || id4 == id2 {
+		t.Fatalf("expired lease %d %v reassigned as %d %v", id2, s2, id4, s4)
+	}
+
+	// the first completion of either lease is accepted
+	if err := c.complete(completion{ID: id1, Min: s1.min, Max: s1.max}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.complete(completion{ID: id3, Min: s3.min, Max: s3.max}); err != errStaleLease {
+		t.Errorf("completion of reassigned lease: %v", err)
+	}
+}
+
+func TestCoordinatorRestart(t *testing.T) {
+	c1, _ := newTestCoordinator(t, time.Minute)
+	id, s, _ := c1.lease(time.Now())
+	c2, _ := newTestCoordinator(t, time.Minute)
+	c2.firstID += 1 << 32 // as if started a second later
+	c2.nextID = c2.firstID
+	c2.lease(time.Now())
+	if err := c2.complete(completion{ID: id, Min: s.min, Max: s.max}); err != errUnknownLease {
+		t.Errorf("completion of lease from before restart: %v", err)
+	}
+}
+
+func TestCoordinatorRejectsUnleased(t *testing.T) {
+	c, name := newTestCoordinator(t, time.Minute)
+	srv := httptest.NewServer(c.handler())
+	defer srv.Close()
+
+	var l leaseResponse
+	resp, err := http.Post(srv.URL+"/lease", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&l)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining := c.sched.remaining()
+
+	for _, r := range []completion{
+		{ID: l.ID, Min: l.Min, Max: c.sched.end},
+		{ID: l.ID, Min: l.Max, Max: l.Max + 0x400},
+		{ID: l.ID + 1, Min: l.Max, Max: l.Max + 0x400},
+		{ID: l.ID, Min: l.Min, Max: l.Max, Found: true, Key: testKey ^ 0x0200000000000000},
+	} {
+		body, _ := json.Marshal(r)
+		resp, err := http.Post(srv.URL+"/complete", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("completion %+v: %s", r, resp.Status)
+		}
+	}
+
+	if n := c.sched.remaining(); n != remaining {
+		t.Errorf("remaining %d want %d", n, remaining)
+	}
+	start, end, step := testSearch()
+	j, done, keys, err := resumeJournal(name, start, end, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+	if len(done) != 0 || len(keys) != 0 {
+		t.Errorf("journaled %v %x", done, keys)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// journal is an append-only log of completed key ranges. Each entry is
+// a single line written with one write call and synced before the
+// range is considered done, so a crash can lose at most the ranges
+// that were still in flight.
+//
+// The first line records the bounds and increment of the search, so
+// that a resumed search uses the same ranges. Entries follow it:
+//
+//	search <start> <end> <step>
+//	done <min> <max>
+//	found <key>
+type journal struct {
+	f  *os.File
+	mu sync.Mutex
+}
+
+// span is a half-open range of permuted keys [min, max).
+type span struct {
+	min, max uint64
+}
+
+// createJournal creates a new journal for a search, refusing to
+// overwrite an existing one.
+func createJournal(name string, start, end, step uint64) (*journal, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL|os.O_APPEND, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("journal %s already exists; use -resume to continue it", name)
+		}
+		return nil, err
+	}
+	j := &journal{f: f}
+	if err := j.header(start, end, step); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// resumeJournal opens an existing journal and returns the completed
+// ranges and any keys found. A torn final entry from an interrupted
+// write is discarded. The journal must have been created for the same
+// search bounds and increment.
+func resumeJournal(name string, start, end, step uint64) (*journal, []span, []uint64, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var done []span
+	var found []uint64
+	var valid int64
+	br := bufio.NewReader(f)
+	for lineno := 1; ; lineno++ {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, nil, nil, err
+		}
+		valid += int64(len(line))
+		fields := strings.Fields(line)
+		if lineno == 1 {
+			if err := checkHeader(fields, start, end, step); err != nil {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("journal %s: %v", name, err)
+			}
+			continue
+		}
+		switch {
+		case len(fields) == 3 && fields[0] == "done":
+			min, err1 := strconv.ParseUint(fields[1], 0, 64)
+			max, err2 := strconv.ParseUint(fields[2], 0, 64)
+			if err1 != nil || err2 != nil || min > max {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("journal %s line %d: bad range: %q", name, lineno, line)
+			}
+			done = append(done, span{min, max})
+		case len(fields) == 2 && fields[0] == "found":
+			key, err := strconv.ParseUint(fields[1], 0, 64)
+			if err != nil {
+				f.Close()
+				return nil, nil, nil, fmt.Errorf("journal %s line %d: bad key: %q", name, lineno, line)
+			}
+			found = append(found, key)
+		default:
+			f.Close()
+			return nil, nil, nil, fmt.Errorf("journal %s line %d: unrecognized entry: %q", name, lineno, line)
+		}
+	}
+	// Drop a partially written trailing entry so that later appends
+	// start on a fresh line.
+	if err := f.Truncate(valid); err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	j := &journal{f: f}
+	if valid == 0 {
+		// the header itself was torn
+		if err := j.header(start, end, step); err != nil {
+			f.Close()
+			return nil, nil, nil, err
+		}
+	}
+	return j, mergeSpans(done), found, nil
+}
+
+// checkHeader checks that the header of a journal records the given
+// search.
+func checkHeader(fields []string, start, end, step uint64) error {
+	if len(fields) != 4 || fields[0] != "search" {
+		return errors.New("missing search header")
+	}
+	want := [3]uint64{start, end, step}
+	for i, name := range []string{"start", "end", "step"} {
+		x, err := strconv.ParseUint(fields[i+1], 0, 64)
+		if err != nil {
+			return fmt.Errorf("bad search header: %q", strings.Join(fields, " "))
+		}
+		if x != want[i] {
+			return fmt.Errorf("journal has -%s 0x%x, not 0x%x", name, x, want[i])
+		}
+	}
+	return nil
+}
+
+// header durably records the search bounds and increment.
+func (j *journal) header(start, end, step uint64) error {
+	return j.write(fmt.Sprintf("search 0x%x 0x%x 0x%x\n", start, end, step))
+}
+
+// complete durably records that [min, max) has been searched.
+func (j *journal) complete(min, max uint64) error {
+	return j.write(fmt.Sprintf("done 0x%x 0x%x\n", min, max))
+}
+
+// found durably records a found key.
+func (j *journal) found(key uint64) error {
+	return j.write(fmt.Sprintf("found 0x%x\n", key))
+}
+
+func (j *journal) write(entry string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.f.WriteString(entry); err != nil {
+		return err
+	}
+	return j.f.Sync()
+}
+
+func (j *journal) Close() error {
+	return j.f.Close()
+}
+
+// mergeSpans sorts spans and coalesces overlapping or adjacent ones.
+func mergeSpans(spans []span) []span {
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].min < spans[j].min })
+	merged := []span{spans[0]}
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.min <= last.max {
+			if s.max > last.max {
+				last.max = s.max
+			}
+		} else {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// scheduler hands out ranges of at most step keys from [next, end),
+// skipping ranges that have already been completed.
+type scheduler struct {
+	next, end, step uint64
+	done            []span // sorted and merged
+}
+
+// take returns the next unsearched range, or false when the keyspace
+// is exhausted.
+func (s *scheduler) take() (min, max uint64, ok bool) {
+	for len(s.done) != 0 && s.done[0].min <= s.next {
+		if s.done[0].max > s.next {
+			s.next = s.done[0].max
+		}
+		s.done = s.done[1:]
+	}
+	if s.next >= s.end {
+		return 0, 0, false
+	}
+	min = s.next
+	max = s.end
+	if s.end-min > s.step {
+		max = min + s.step
+	}
+	if len(s.done) != 0 && s.done[0].min < max {
+		max = s.done[0].min
+	}
+	s.next = max
+	return min, max, true
+}
+
+// remaining returns the number of keys in [next, end) that have not
+// been completed.
+func (s *scheduler) remaining() uint64 {
+	if s.next >= s.end {
+		return 0
+	}
+	n := s.end - s.next
+	for _, d := range s.done {
+		if d.min >= s.end {
+			break
+		}
+		lo, hi := d.min, d.max
+		if lo < s.next {
+			lo = s.next
+		}
+		if hi > s.end {
+			hi = s.end
+		}
+		if lo < hi {
+			n -= hi - lo
+		}
+	}
+	return n
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeJournal(t *testing.T, contents string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "journal")
+	if err := os.WriteFile(name, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestResumeJournal(t *testing.T) {
+	name := writeJournal(t, "search 0x0 0x1000 0x100\n"+
+		"done 0x100 0x200\n"+
+		"done 0x0 0x100\n"+
+		"found 0x1234\n"+
+		"done 0x3")
+	j, done, found, err := resumeJournal(name, 0, 0x1000, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []span{{0, 0x200}}; !reflect.DeepEqual(done, want) {
+		t.Errorf("done %v want %v", done, want)
+	}
+	if want := []uint64{0x1234}; !reflect.DeepEqual(found, want) {
+		t.Errorf("found %x want %x", found, want)
+	}
+
+	// the torn entry is truncated before the next append
+	if err := j.complete(0x300, 0x400); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(b), "found 0x1234\ndone 0x300 0x400\n") {
+		t.Errorf("journal after append:\n%s", b)
+	}
+}
+
+func TestResumeJournalMismatch(t *testing.T) {
+	name := writeJournal(t, "search 0x0 0x1000 0x100\ndone 0x0 0x100\n")
+	for _, tt := range []struct{ start, end, step uint64 }{
+		{0x100, 0x1000, 0x100},
+		{0, 0x2000, 0x100},
+		{0, 0x1000, 0x80},
+	} {
+		if j, _, _, err := resumeJournal(name, tt.start, tt.end, tt.step); err == nil {
+			j.Close()
+			t.Errorf("resumed with %+v", tt)
+		}
+	}
+	name = writeJournal(t, "done 0x0 0x100\n")
+	if j, _, _, err := resumeJournal(name, 0, 0x1000, 0x100); err == nil {
+		j.Close()
+		t.Error("resumed without header")
+	}
+}
+
+func TestMergeSpans(t *testing.T) {
+	spans := []span{{0x50, 0x60}, {0x10, 0x20}, {0x20, 0x30}, {0x40, 0x58}, {0x12, 0x18}, {0x70, 0x80}}
+	want := []span{{0x10, 0x30}, {0x40, 0x60}, {0x70, 0x80}}
+	if got := mergeSpans(spans); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeSpans = %v want %v", got, want)
+	}
+	if got := mergeSpans(nil); got != nil {
+		t.Errorf("mergeSpans(nil) = %v", got)
+	}
+}
+
+func TestSchedulerTake(t *testing.T) {
+	s := &scheduler{next: 0, end: 0x100, step: 0x40, done: []span{
+		{0, 0x10},    // skipped at the start
+		{0x30, 0x50}, // clips the first chunk
+		{0x90, 0xa0},
+		{0xf0, 0x120}, // extends past the end
+	}}
+	if n := s.remaining(); n != 0x100-0x10-0x20-0x10-0x10 {
+		t.Errorf("remaining = 0x%x", n)
+	}
+	var got []span
+	for {
+		min, max, ok := s.take()
+		if !ok {
+			break
+		}
+		got = append(got, span{min, max})
+	}
+	want := []span{{0x10, 0x30}, {0x50, 0x90}, {0xa0, 0xe0}, {0xe0, 0xf0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("take = %v want %v", got, want)
+	}
+	if n := s.remaining(); n != 0 {
+		t.Errorf("remaining after take = 0x%x", n)
+	}
+}
+
+func TestSchedulerRemaining(t *testing.T) {
+	s := &scheduler{next: 0x20, end: 0x100, step: 0x40, done: []span{{0x10, 0x30}, {0x80, 0x90}}}
+	if n := s.remaining(); n != 0xe0-0x10-0x10 {
+		t.Errorf("remaining = 0x%x", n)
+	}
+}
+
+func TestResumeJournalTornHeader(t *testing.T) {
+	name := writeJournal(t, "search 0x0 0x1")
+	j, done, _, err := resumeJournal(name, 0, 0x1000, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+	if b, _ := os.ReadFile(name); string(b) != "search 0x0 0x1000 0x100\n" || done != nil {
+		t.Errorf("journal %q, done %v", b, done)
+	}
+}
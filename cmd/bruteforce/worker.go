@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+// keySearcher is implemented by des.Cracker and des.BitsliceCracker.
+type keySearcher interface {
+	SearchKey(min, max uint64) (key uint64, ok bool)
+}
+
+func newSearcher(in, out uint64) keySearcher {
+	if bitslice {
+		return des.NewBitsliceCracker(in, out)
+	}
+	return des.NewCracker(in, out)
+}
+
+// remoteWorker searches ranges leased from a coordinator.
+type remoteWorker struct {
+	url    string
+	client *http.Client
+
+	mu       sync.Mutex
+	crackers map[[2]uint64]keySearcher
+}
+
+// runWorker searches leased ranges on each core until the coordinator
+// reports that the search is over or cannot be reached.
+func runWorker(url string, workers int) {
+	w := &remoteWorker{
+		url:      url,
+		client:   &http.Client{Timeout: time.Minute},
+		crackers: make(map[[2]uint64]keySearcher),
+	}
+	log.Printf("Searching for coordinator %s with %d workers\n", url, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.run(); err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *remoteWorker) run() error {
+	failures := 0
+	for {
+		l, status, err := w.lease()
+		if err != nil {
+			// tolerate brief outages of the coordinator
+			if failures++; failures > 3 {
+				return err
+			}
+			time.Sleep(time.Duration(failures) * time.Second)
+			continue
+		}
+		failures = 0
+		switch status {
+		case http.StatusGone:
+			return nil
+		case http.StatusNoContent:
+			time.Sleep(time.Second)
+			continue
+		}
+
+		t := time.Now()
+		r := completion{ID: l.ID, Min: l.Min, Max: l.Max}
+		r.Key, r.Found = w.cracker(l.Plain, l.Cipher).SearchKey(l.Min, l.Max)
+		if r.Found {
+			log.Printf("Found key 0x%x\n", r.Key)
+		} else {
+			d := time.Since(t)
+			log.Printf("Searched 0x%x to 0x%x in %v, %v/op\n", l.Min, l.Max, d, d/time.Duration(l.Max-l.Min))
+		}
+		if err := w.complete(r); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *remoteWorker) cracker(in, out uint64) keySearcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	c, ok := w.crackers[[2]uint64{in, out}]
+	if !ok {
+		c = newSearcher(in, out)
+		w.crackers[[2]uint64{in, out}] = c
+	}
+	return c
+}
+
+func (w *remoteWorker) lease() (leaseResponse, int, error) {
+	var l leaseResponse
+	resp, err := w.client.Post(w.url+"/lease", "application/json", nil)
+	if err != nil {
+		return l, 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+			return l, 0, err
+		}
+		if l.Min >= l.Max {
+			return l, 0, fmt.Errorf("empty lease 0x%x to 0x%x", l.Min, l.Max)
+		}
+	case http.StatusNoContent, http.StatusGone:
+	default:
+		return l, 0, fmt.Errorf("lease: %s", resp.Status)
+	}
+	return l, resp.StatusCode, nil
+}
+
+func (w *remoteWorker) complete(r completion) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	for failures := 0; ; failures++ {
+		resp, err := w.client.Post(w.url+"/complete", "application/json", bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			switch resp.StatusCode {
+			case http.StatusNoContent:
+				return nil
+			case http.StatusConflict, http.StatusBadRequest:
+				// another worker completed the reassigned range first, or
+				// the coordinator restarted and no longer knows the lease
+				log.Printf("Completion of 0x%x to 0x%x rejected: %s\n", r.Min, r.Max, resp.Status)
+				return nil
+			}
+			return fmt.Errorf("complete: %s", resp.Status)
+		}
+		if failures == 3 {
+			return err
+		}
+		time.Sleep(time.Duration(failures+1) * time.Second)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+)
+
+var (
+	storeDir string
+	build    bool
+	uid      int64
+	email    string
+	password string
+)
+
+// Indexed store of parsed credentials. With -build, the dump is parsed
+// into the store; otherwise the store is queried by uid, email, or
+// base64 encrypted password and the matching creds are written as CSV.
+func main() {
+	flag.StringVar(&storeDir, "store", "credstore", "store directory")
+	flag.BoolVar(&build, "build", false, "build the store from the dump")
+	flag.Int64Var(&uid, "uid", 0, "find creds by uid")
+	flag.StringVar(&email, "email", "", "find creds by email")
+	flag.StringVar(&password, "password", "", "find creds by base64 encrypted password")
+	flag.Parse()
+
+	uidSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "uid" {
+			uidSet = true
+		}
+	})
+	if uidSet && (uid < math.MinInt32 || uid > math.MaxInt32) {
+		fmt.Fprintln(os.Stderr, "-uid out of range:", uid)
+		os.Exit(2)
+	}
+
+	if build {
+		buildStore(flag.Arg(0))
+		return
+	}
+
+	s, err := adobe.OpenStore(storeDir)
+	try(err)
+	defer s.Close()
+
+	var rows []int
+	switch {
+	case uidSet:
+		rows, err = s.FindUID(int32(uid))
+	case email != "":
+		rows, err = s.FindEmail(email)
+	case password != "":
+		b, derr := base64.StdEncoding.DecodeString(password)
+		try(derr)
+		rows, err = s.FindPassword(b)
+	default:
+		fmt.Fprintln(os.Stderr, "one of -build, -uid, -email, or -password is required")
+		os.Exit(2)
+	}
+	try(err)
+
+	w := csv.NewWriter(os.Stdout)
+	for _, row := range rows {
+		c, err := s.Get(row)
+		try(err)
+		try(w.Write([]string{
+			strconv.Itoa(int(c.UID)),
+			c.Username,
+			c.Email,
+			base64.StdEncoding.EncodeToString(c.Password),
+			c.Hint,
+		}))
+	}
+	w.Flush()
+	try(w.Error())
+}
+
+func buildStore(filename string) {
+	f, err := adobe.OpenCredFile(filename)
+	try(err)
+	defer f.Close()
+
+	w, err := adobe.CreateStore(storeDir)
+	try(err)
+	cr := adobe.NewCredReader(f)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err, record)
+			continue
+		}
+		try(w.Add(cred))
+	}
+	try(w.Close())
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -2,43 +2,50 @@ package main
 
 import (
 	"encoding/csv"
+	"flag"
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
 	"github.com/andrewarchi/adobe-cred/adobe"
 )
 
+var (
+	lenient        bool
+	quarantineFile string
+	maxLines       int
+)
+
 func main() {
-	var filename string
-	if len(os.Args) >= 2 {
-		filename = os.Args[1]
+	flag.BoolVar(&lenient, "lenient", false, "skip malformed rows instead of exiting")
+	flag.StringVar(&quarantineFile, "quarantine", "rejected.txt", "file for rows skipped in lenient mode")
+	flag.IntVar(&maxLines, "maxlines", adobe.DefaultMaxLines, "maximum lines joined to form a row")
+	flag.Parse()
+
+	filename := flag.Arg(0)
+	if filename != "" {
 		if _, err := os.Stat(filename); os.IsNotExist(err) {
 			fmt.Fprintf(os.Stderr, "%s not found\n", filename)
 			os.Exit(2)
 		}
-	} else if _, err := os.Stat("cred"); err == nil {
-		filename = "cred"
-	} else if _, err := os.Stat("users.tar.gz"); err == nil {
-		filename = "users.tar.gz"
-	} else {
-		fmt.Fprintln(os.Stderr, "cred or users.tar.gz not found")
-		os.Exit(2)
 	}
 
-	f, err := os.Open(filename)
+	f, err := adobe.OpenCredFile(filename)
+	if err == adobe.ErrNoCredFile {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 	try(err)
 	defer f.Close()
 
-	var r io.Reader = f
-	if strings.HasSuffix(filename, ".tar.gz") || strings.HasSuffix(filename, ".tgz") {
-		tr, err := adobe.NewUsersTarGZReader(f)
+	cr := adobe.NewCredReader(f)
+	cr.MaxLines = maxLines
+	if lenient {
+		qf, err := os.Create(quarantineFile)
 		try(err)
-		r = tr
+		defer qf.Close()
+		cr.Quarantine = adobe.NewQuarantine(qf)
 	}
-
-	cr := adobe.NewCredReader(r)
 	w := csv.NewWriter(os.Stdout)
 	for {
 		record, err := cr.Read()
@@ -51,6 +58,15 @@ func main() {
 		}
 		try(w.Write(record))
 	}
+	w.Flush()
+	try(w.Error())
+
+	if q := cr.Quarantine; q != nil {
+		fmt.Fprintf(os.Stderr, "%d rows rejected\n", q.Total())
+		for _, r := range q.Reasons() {
+			fmt.Fprintf(os.Stderr, "%d\t%s\n", r.Count, r.Reason)
+		}
+	}
 }
 
 func try(err error) {
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+)
+
+// finalBlocks is a flag of known final blocks in the form <hex>=<n>,
+// where n is the number of plaintext bytes before the padding.
+type finalBlocks map[uint64]int
+
+func (f finalBlocks) String() string {
+	var s []string
+	for block, n := range f {
+		s = append(s, fmt.Sprintf("%016x=%d", block, n))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+func (f finalBlocks) Set(value string) error {
+	i := strings.IndexByte(value, '=')
+	if i == -1 {
+		return fmt.Errorf("final block %q not in the form <hex>=<n>", value)
+	}
+	block, err := strconv.ParseUint(value[:i], 16, 64)
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(value[i+1:])
+	if err != nil {
+		return err
+	}
+	if n < 0 || n > 7 {
+		return fmt.Errorf("final block %q: plaintext length must be 0 to 7", value)
+	}
+	f[block] = n
+	return nil
+}
+
+// Statistics on password lengths in the credential dump.
+func main() {
+	final := make(finalBlocks)
+	flag.Var(final, "final", "known final block as <hex>=<plaintext bytes> (repeatable)")
+	flag.Parse()
+
+	f, err := adobe.OpenCredFile(flag.Arg(0))
+	try(err)
+	defer f.Close()
+
+	l := adobe.NewLengthInferrer()
+	for block, n := range final {
+		l.AddFinalBlock(block, n)
+	}
+
+	var records, invalid, empty, unaligned int
+	cipherLengths := make(map[int]int)
+	lengths := adobe.NewLengthStats()
+	cr := adobe.NewCredReader(f)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		records++
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			invalid++
+			continue
+		}
+		if len(cred.Password) == 0 {
+			empty++
+			continue
+		}
+		cipherLengths[len(cred.Password)]++
+		if len(cred.Password)%8 != 0 {
+			unaligned++
+			continue
+		}
+		lengths.Add(l.Length(cred.Password))
+	}
+
+	fmt.Printf("records\t%d\n", records)
+	fmt.Printf("invalid\t%d\n", invalid)
+	fmt.Printf("empty passwords\t%d\n", empty)
+	fmt.Printf("unaligned passwords\t%d\n", unaligned)
+
+	fmt.Println("\ncipher text length\tcount")
+	var keys []int
+	for n := range cipherLengths {
+		keys = append(keys, n)
+	}
+	sort.Ints(keys)
+	for _, n := range keys {
+		fmt.Printf("%d\t%d\n", n, cipherLengths[n])
+	}
+
+	fmt.Println("\nplaintext length\tcount")
+	exact := 0
+	for _, c := range lengths.Counts() {
+		fmt.Printf("%v\t%d\n", c.Range, c.Count)
+		if c.Range.Exact() {
+			exact += c.Count
+		}
+	}
+	fmt.Printf("\nexact lengths\t%d\n", exact)
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+	"github.com/andrewarchi/adobe-cred/internal/block"
+)
+
+var (
+	plain uint64 = block.Password
+
+	tableFile string
+	generate  int
+	dpBits    uint
+	maxLen    int
+	variant   uint64
+)
+
+// Distinguished point tables for DES under a fixed plaintext. With
+// -gen, chains are added to the table, creating it if it does not
+// exist; otherwise the cipher texts given in hex as arguments are
+// looked up in the table.
+func main() {
+	flag.StringVar(&tableFile, "table", "des.dpt", "distinguished point table file")
+	flag.IntVar(&generate, "gen", 0, "number of chains to attempt adding to the table")
+	flag.Var((*block.Hex)(&plain), "plain", "plaintext block in hex, for a new table")
+	flag.UintVar(&dpBits, "dpbits", 16, "low zero bits of distinguished points, for a new table")
+	flag.IntVar(&maxLen, "maxlen", 1<<20, "maximum keys per chain, for a new table")
+	flag.Uint64Var(&variant, "variant", 0, "reduction function variant, for a new table")
+	flag.Parse()
+
+	log.SetOutput(os.Stdout)
+	t, err := readTable(tableFile)
+	if generate != 0 {
+		if os.IsNotExist(err) {
+			t, err = des.NewDPTable(plain, dpBits, maxLen, variant), nil
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		t0 := time.Now()
+		added := t.Generate(generate)
+		log.Printf("Added %d of %d chains in %v, table has %d chains covering %d keys\n",
+			added, generate, time.Since(t0), t.Len(), t.Keys())
+		try(writeTable(tableFile, t))
+		return
+	}
+	try(err)
+
+	for _, arg := range flag.Args() {
+		var out block.Hex
+		try(out.Set(arg))
+		t0 := time.Now()
+		if key, ok := t.Lookup(uint64(out)); ok {
+			log.Printf("%s: found key 0x%016x in %v\n", arg, key, time.Since(t0))
+		} else {
+			log.Printf("%s: key not found in %v\n", arg, time.Since(t0))
+		}
+	}
+}
+
+func readTable(filename string) (*des.DPTable, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return des.ReadDPTable(f)
+}
+
+// writeTable replaces the table file, writing to a temporary file
+// first so that an interrupted write does not lose earlier chains.
+func writeTable(filename string, t *des.DPTable) error {
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := t.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+func try(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+	"github.com/andrewarchi/adobe-cred/des"
+	"github.com/andrewarchi/adobe-cred/rules"
+)
+
+var (
+	minUsers  int
+	top       int
+	padding   string
+	rulesFile string
+)
+
+// Known plaintext guesses from hints. For each password shared by
+// several users, every guess suggested by its hints that matches the
+// encrypted length is split into blocks, and each new pair is written as
+// a tab-separated line of hex plaintext block, hex cipher text block,
+// users, base64 password, and guess. Hints that refer to the user's own
+// email or username give guesses for that user's password alone.
+func main() {
+	flag.IntVar(&minUsers, "min", 2, "minimum users sharing a password")
+	flag.IntVar(&top, "top", 10, "maximum hints per password")
+	flag.StringVar(&padding, "padding", "pkcs5", "padding scheme: pkcs5 or null")
+	flag.StringVar(&rulesFile, "rules", "", "hashcat rule file applied to each guess")
+	flag.Parse()
+
+	var p adobe.Padding
+	switch padding {
+	case "pkcs5":
+		p = adobe.PKCS5Padding
+	case "null":
+		p = adobe.NullPadding
+	default:
+		fmt.Fprintf(os.Stderr, "unknown padding: %s\n", padding)
+		os.Exit(2)
+	}
+
+	var rs rules.Rules
+	if rulesFile != "" {
+		rf, err := os.Open(rulesFile)
+		try(err)
+		rs, err = rules.ReadRules(rf)
+		rf.Close()
+		try(err)
+	}
+
+	f, err := adobe.OpenCredFile(flag.Arg(0))
+	try(err)
+	defer f.Close()
+
+	w := bufio.NewWriter(os.Stdout)
+	seen := make(map[des.Pair]bool)
+	emit := func(cipher []byte, users int, guesses []string) {
+		password := base64.StdEncoding.EncodeToString(cipher)
+		for _, guess := range guesses {
+			for it := rs.Apply([]byte(guess)); it.Next(); {
+				guess := string(it.Word())
+				for _, pair := range adobe.GuessPairs(cipher, guess, p) {
+					if seen[pair] {
+						continue
+					}
+					seen[pair] = true
+					_, err := fmt.Fprintf(w, "%016x\t%016x\t%d\t%s\t%q\n", pair.In, pair.Out, users, password, guess)
+					try(err)
+				}
+			}
+		}
+	}
+
+	h := adobe.NewHintAggregator()
+	cr := adobe.NewCredReader(f)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err, record)
+			continue
+		}
+		h.Add(cred)
+		// hints like "same as email" only apply to their own account
+		emit(cred.Password, 1, adobe.AccountGuesses(cred))
+	}
+
+	for _, g := range h.PasswordGroups(minUsers, top) {
+		for _, hint := range g.Hints {
+			emit(g.Cipher, g.Users, adobe.HintGuesses(hint.Hint))
+		}
+	}
+	try(w.Flush())
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+)
+
+var (
+	passwordsFile string
+	blocksFile    string
+	minUsers      int
+	top           int
+)
+
+// Aggregation of hints by shared encrypted password and block.
+func main() {
+	flag.StringVar(&passwordsFile, "passwords", "password_hints.tsv", "output file for hints per password")
+	flag.StringVar(&blocksFile, "blocks", "block_hints.tsv", "output file for hints per block")
+	flag.IntVar(&minUsers, "min", 2, "minimum users sharing a cipher text")
+	flag.IntVar(&top, "top", 10, "maximum hints per cipher text")
+	flag.Parse()
+
+	f, err := adobe.OpenCredFile(flag.Arg(0))
+	try(err)
+	defer f.Close()
+
+	h := adobe.NewHintAggregator()
+	cr := adobe.NewCredReader(f)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err, record)
+			continue
+		}
+		h.Add(cred)
+	}
+
+	out, err := os.Create(passwordsFile)
+	try(err)
+	try(adobe.WriteHintGroups(out, h.PasswordGroups(minUsers, top)))
+	try(out.Close())
+
+	out, err = os.Create(blocksFile)
+	try(err)
+	try(adobe.WriteHintGroups(out, h.BlockGroups(minUsers, top)))
+	try(out.Close())
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+	"github.com/andrewarchi/adobe-cred/internal/block"
+)
+
+var (
+	keys   string
+	plain  uint64
+	cipher uint64
+	sample int
+)
+
+// Cipher hypothesis tester. Given up to three candidate keys, the
+// passwords in the dump are decrypted under each interpretation of the
+// keys as DES or Triple DES with various key orderings, modes, and byte
+// orders. Interpretations are listed by decreasing passwords that
+// decrypt to printable plaintexts with valid padding. When a known
+// plaintext and cipher text block are given, interpretations that do
+// not reproduce it are marked.
+func main() {
+	flag.StringVar(&keys, "keys", "", "comma-separated candidate keys in hex")
+	flag.Var((*block.Hex)(&plain), "plain", "known plaintext block in hex")
+	flag.Var((*block.Hex)(&cipher), "cipher", "known cipher text block in hex")
+	flag.IntVar(&sample, "sample", 10000, "passwords to decrypt, or 0 for all")
+	flag.Parse()
+
+	var ks []uint64
+	for _, s := range strings.Split(keys, ",") {
+		k, err := strconv.ParseUint(s, 16, 64)
+		try(err)
+		ks = append(ks, k)
+	}
+	if len(ks) > 3 {
+		fmt.Fprintln(os.Stderr, "at most three keys")
+		os.Exit(2)
+	}
+	interps := adobe.Interpretations(ks)
+	pair := plain != 0 || cipher != 0
+	reproduces := make(map[string]bool)
+	for _, in := range interps {
+		reproduces[in.Name] = in.Cipher.EncryptBlock(plain) == cipher
+	}
+
+	f, err := adobe.OpenCredFile(flag.Arg(0))
+	try(err)
+	defer f.Close()
+
+	h := adobe.NewHypothesisTester(interps)
+	cr := adobe.NewCredReader(f)
+	for n := 0; sample == 0 || n < sample; {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err, record)
+			continue
+		}
+		if len(cred.Password) != 0 {
+			h.Add(cred)
+			n++
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	_, err = fmt.Fprintln(w, "printable\tpkcs5\tnull\tpasswords\tpair\tinterpretation")
+	try(err)
+	for _, s := range h.Scores() {
+		match := "-"
+		if pair {
+			match = strconv.FormatBool(reproduces[s.Name])
+		}
+		_, err := fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%s\t%s\n", s.Printable, s.PKCS5, s.Null, s.Passwords, match, s.Name)
+		try(err)
+	}
+	try(w.Flush())
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
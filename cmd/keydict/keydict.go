@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+	"github.com/andrewarchi/adobe-cred/internal/block"
+	"github.com/andrewarchi/adobe-cred/rules"
+)
+
+var (
+	cipher uint64 = 0x2fca9b003de39778
+	plain  uint64 = block.Password
+
+	mangle    string
+	rulesFile string
+)
+
+// DES search of keys formed from wordlists. Words are read from the
+// files given as arguments, or stdin, one per line.
+func main() {
+	flag.Var((*block.Hex)(&plain), "plain", "plaintext block in hex")
+	flag.Var((*block.Hex)(&cipher), "cipher", "cipher text block in hex")
+	flag.StringVar(&mangle, "mangle", "all", "comma-separated mangling rules: case, leet, digits, all, or none")
+	flag.StringVar(&rulesFile, "rules", "", "hashcat rule file applied to each word before mangling")
+	flag.Parse()
+
+	m, err := parseMangle(mangle)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var rs rules.Rules
+	if rulesFile != "" {
+		f, err := os.Open(rulesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rs, err = rules.ReadRules(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	rs = rules.Product(rs, m.Rules())
+
+	// found is closed on the first match to stop reading words
+	words := make(chan []string, 64)
+	found := make(chan struct{})
+	go func() {
+		defer close(words)
+		if flag.NArg() == 0 {
+			readWords(os.Stdin, words, found)
+			return
+		}
+		for _, filename := range flag.Args() {
+			f, err := os.Open(filename)
+			if err != nil {
+				log.Fatal(err)
+			}
+			ok := readWords(f, words, found)
+			f.Close()
+			if !ok {
+				return
+			}
+		}
+	}()
+
+	log.SetOutput(os.Stdout)
+	workers := runtime.GOMAXPROCS(-1)
+	log.Printf("Searching with %d workers\n", workers)
+	t0 := time.Now()
+	c := des.NewCracker(plain, cipher)
+
+	var (
+		count, keys uint64
+		done        int32
+		wg          sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range words {
+				if atomic.LoadInt32(&done) != 0 {
+					return
+				}
+				m, n, ok := c.CheckWords(&wordList{words: batch}, rs)
+				atomic.AddUint64(&keys, uint64(n))
+				if ok && atomic.CompareAndSwapInt32(&done, 0, 1) {
+					log.Printf("Found key 0x%016x from %q (word %q) in %v\n", m.Key, m.Variant, m.Word, time.Since(t0))
+					close(found)
+				}
+				if n := atomic.AddUint64(&count, uint64(len(batch))); n&^0xfffff != (n-uint64(len(batch)))&^0xfffff {
+					d := time.Since(t0)
+					log.Printf("Tried %d words in %v, %.0f keys/s\n", n, d, float64(atomic.LoadUint64(&keys))/d.Seconds())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	d := time.Since(t0)
+	log.Printf("Tried %d keys in %v, %.0f keys/s\n", keys, d, float64(keys)/d.Seconds())
+	if done == 0 {
+		log.Printf("Key not found in %v\n", d)
+	}
+}
+
+// readWords sends the lines of r in batches and returns false if
+// stopped before reaching the end.
+func readWords(r io.Reader, words chan<- []string, stop <-chan struct{}) bool {
+	const batchSize = 1024
+	s := bufio.NewScanner(r)
+	batch := make([]string, 0, batchSize)
+	for s.Scan() {
+		batch = append(batch, s.Text())
+		if len(batch) == batchSize {
+			select {
+			case words <- batch:
+			case <-stop:
+				return false
+			}
+			batch = make([]string, 0, batchSize)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if len(batch) != 0 {
+		select {
+		case words <- batch:
+		case <-stop:
+			return false
+		}
+	}
+	return true
+}
+
+// wordList is a des.WordScanner over a batch of words.
+type wordList struct {
+	words []string
+	word  []byte
+}
+
+func (l *wordList) Scan() bool {
+	if len(l.words) == 0 {
+		return false
+	}
+	l.word = append(l.word[:0], l.words[0]...)
+	l.words = l.words[1:]
+	return true
+}
+
+func (l *wordList) Bytes() []byte {
+	return l.word
+}
+
+func parseMangle(s string) (des.Mangle, error) {
+	var m des.Mangle
+	for _, rule := range strings.Split(s, ",") {
+		switch rule {
+		case "case":
+			m |= des.MangleCase
+		case "leet":
+			m |= des.MangleLeet
+		case "digits":
+			m |= des.MangleDigits
+		case "all":
+			m |= des.MangleAll
+		case "none", "":
+		default:
+			return 0, fmt.Errorf("unknown mangling rule: %s", rule)
+		}
+	}
+	return m, nil
+}
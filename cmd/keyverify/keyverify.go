@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andrewarchi/adobe-cred/adobe"
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+var (
+	keys    string
+	padding string
+	sample  int
+	seed    int64
+	show    int
+)
+
+// Candidate key verifier. A key found from a single known pair may be
+// a false positive, so passwords in the dump are decrypted with it and
+// checked for valid padding, printable plaintext, and agreement with
+// their hints. One key is DES and two or three keys are Triple DES in
+// EDE mode. The sample is drawn uniformly from the whole dump by
+// reservoir sampling, so the entire dump is read either way.
+func main() {
+	flag.StringVar(&keys, "key", "", "comma-separated candidate keys in hex")
+	flag.StringVar(&padding, "padding", "pkcs5", "padding scheme: pkcs5 or null")
+	flag.IntVar(&sample, "sample", 10000, "passwords to decrypt, sampled uniformly, or 0 for all")
+	flag.Int64Var(&seed, "seed", 1, "random seed for sampling")
+	flag.IntVar(&show, "show", 10, "decrypted passwords to print")
+	flag.Parse()
+
+	var p adobe.Padding
+	switch padding {
+	case "pkcs5":
+		p = adobe.PKCS5Padding
+	case "null":
+		p = adobe.NullPadding
+	default:
+		fmt.Fprintf(os.Stderr, "unknown padding: %s\n", padding)
+		os.Exit(2)
+	}
+
+	var ks []uint64
+	for _, s := range strings.Split(keys, ",") {
+		k, err := strconv.ParseUint(s, 16, 64)
+		try(err)
+		ks = append(ks, k)
+	}
+	var c adobe.BlockCipher
+	switch len(ks) {
+	case 1:
+		c = des.NewCipher(ks[0])
+	case 2:
+		c = des.NewTripleDESCipher([3]uint64{ks[0], ks[1], ks[0]})
+	case 3:
+		c = des.NewTripleDESCipher([3]uint64{ks[0], ks[1], ks[2]})
+	default:
+		fmt.Fprintln(os.Stderr, "expected one to three keys")
+		os.Exit(2)
+	}
+
+	f, err := adobe.OpenCredFile(flag.Arg(0))
+	try(err)
+	defer f.Close()
+
+	v := adobe.NewKeyVerifier(c, p)
+	verify := func(cred *adobe.Cred) {
+		if plain, ok := v.Add(cred); ok && show > 0 {
+			fmt.Printf("%d\t%q\t%q\n", cred.UID, plain, cred.Hint)
+			show--
+		}
+	}
+
+	var reservoir []*adobe.Cred
+	rng := rand.New(rand.NewSource(seed))
+	n := 0
+	cr := adobe.NewCredReader(f)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		try(err)
+		cred, err := adobe.ParseRecord(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err, record)
+			continue
+		}
+		if sample == 0 {
+			verify(cred)
+			continue
+		}
+		if len(cred.Password) == 0 || len(cred.Password)%8 != 0 {
+			continue
+		}
+		n++
+		if len(reservoir) < sample {
+			reservoir = append(reservoir, cred)
+		} else if j := rng.Intn(n); j < sample {
+			reservoir[j] = cred
+		}
+	}
+	for _, cred := range reservoir {
+		verify(cred)
+	}
+
+	r := v.Report()
+	fmt.Printf("Passwords:    %d\n", r.Passwords)
+	fmt.Printf("Padded:       %d\n", r.Padded)
+	fmt.Printf("Printable:    %d\n", r.Printable)
+	fmt.Printf("Hint matches: %d of %d (%.2f%%)\n", r.HintMatches, r.Hinted, 100*r.HintRate())
+	fmt.Printf("Confidence:   %.2f%% (printable, scaled by hint matches)\n", 100*r.Confidence())
+}
+
+func try(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+)
+
+var (
+	mode      string
+	k1, k2    keyRange
+	k3        keyRange
+	pairsFile string
+	a         uint64
+	tries     int
+	demo      bool
+	tmpDir    string
+)
+
+// Meet-in-the-middle attacks on Triple DES over reduced key spaces,
+// given as ranges of permuted keys. Known pairs are read as the hex
+// plaintext and cipher text in the first two columns of a tab-separated
+// file, such as the output of hintguess. With -demo, random keys are
+// chosen from the key spaces and a chosen plaintext attack is run.
+func main() {
+	flag.StringVar(&mode, "mode", "2key", "attack: 2key for two-key EDE or 3key for three-key EDE")
+	flag.Var(&k1, "k1", "range of permuted K1 as hex min:max")
+	flag.Var(&k2, "k2", "range of permuted K2 as hex min:max")
+	flag.Var(&k3, "k3", "range of permuted K3 as hex min:max, for 3key")
+	flag.StringVar(&pairsFile, "pairs", "", "file of known plaintext and cipher text pairs")
+	flag.Uint64Var(&a, "a", 0, "first intermediate value, for 2key")
+	flag.IntVar(&tries, "tries", 1, "intermediate values to try, for 2key with known pairs")
+	flag.BoolVar(&demo, "demo", false, "attack random keys with a chosen plaintext oracle")
+	flag.StringVar(&tmpDir, "tmp", "", "directory for temporary tables")
+	flag.Parse()
+
+	log.SetOutput(os.Stdout)
+	opts := &des.MITMOptions{Dir: tmpDir}
+	if mode != "2key" && mode != "3key" {
+		log.Fatalf("unknown mode: %s", mode)
+	}
+
+	var pairs []des.Pair
+	var oracle func(in uint64) (uint64, bool)
+	if demo {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		key := [3]uint64{k1.random(rng), k2.random(rng), k3.random(rng)}
+		if mode == "2key" {
+			key[2] = key[0]
+		}
+		log.Printf("Demo keys %016x %016x %016x\n", key[0], key[1], key[2])
+		c := des.NewTripleDESCipher(key)
+		for i := 0; i < 3; i++ {
+			in := rng.Uint64()
+			pairs = append(pairs, des.Pair{In: in, Out: c.EncryptBlock(in)})
+		}
+		oracle = func(in uint64) (uint64, bool) { return c.EncryptBlock(in), true }
+	} else {
+		var err error
+		if pairs, err = readPairs(pairsFile); err != nil {
+			log.Fatal(err)
+		}
+		if len(pairs) == 0 {
+			log.Fatal("no known pairs; use -pairs or -demo")
+		}
+		known := make(map[uint64]uint64)
+		for _, p := range pairs {
+			known[p.In] = p.Out
+		}
+		oracle = func(in uint64) (uint64, bool) {
+			out, ok := known[in]
+			return out, ok
+		}
+	}
+
+	t0 := time.Now()
+	var keys [][3]uint64
+	if mode == "2key" {
+		n := tries
+		if demo {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			found, err := des.MITM2Key(des.KeyRange(k1), des.KeyRange(k2), a+uint64(i), oracle, nil, opts)
+			if err != nil {
+				log.Fatal(err)
+			}
+			keys = append(keys, found...)
+		}
+	} else {
+		var err error
+		keys, err = des.MITM3Key(des.KeyRange(k1), des.KeyRange(k2), des.KeyRange(k3), pairs[0], nil, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	log.Printf("Found %d candidate keys in %v\n", len(keys), time.Since(t0))
+
+	// guessed pairs may be wrong, so report how many each key encrypts
+	for _, key := range keys {
+		c := des.NewTripleDESCipher(key)
+		n := 0
+		for _, p := range pairs {
+			if c.EncryptBlock(p.In) == p.Out {
+				n++
+			}
+		}
+		fmt.Printf("%016x %016x %016x\t%d of %d pairs\n", key[0], key[1], key[2], n, len(pairs))
+	}
+}
+
+func readPairs(filename string) ([]des.Pair, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var pairs []des.Pair
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Split(s.Text(), "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: want plaintext and cipher text", filename, line)
+		}
+		in, err1 := strconv.ParseUint(fields[0], 16, 64)
+		out, err2 := strconv.ParseUint(fields[1], 16, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("%s:%d: invalid hex block", filename, line)
+		}
+		pairs = append(pairs, des.Pair{In: in, Out: out})
+	}
+	return pairs, s.Err()
+}
+
+// keyRange is a flag.Value for a range of permuted keys.
+type keyRange des.KeyRange
+
+func (r *keyRange) random(rng *rand.Rand) uint64 {
+	kr := des.KeyRange(*r)
+	if kr.Len() == 0 {
+		return 0
+	}
+	return kr.Key(uint64(rng.Int63n(int64(kr.Len()))))
+}
+
+func (r *keyRange) String() string {
+	return fmt.Sprintf("%x:%x", r.Min, r.Max)
+}
+
+func (r *keyRange) Set(s string) error {
+	i := strings.IndexByte(s, ':')
+	if i == -1 {
+		return fmt.Errorf("range %q is not min:max", s)
+	}
+	min, err := strconv.ParseUint(s[:i], 16, 64)
+	if err != nil {
+		return err
+	}
+	max, err := strconv.ParseUint(s[i+1:], 16, 64)
+	if err != nil {
+		return err
+	}
+	r.Min, r.Max = min, max
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/andrewarchi/adobe-cred/des"
+	"github.com/andrewarchi/adobe-cred/internal/block"
+)
+
+var (
+	plain uint64 = block.Password
+
+	tableFile string
+	generate  bool
+	chainLen  int
+	chains    int
+	start     uint64
+)
+
+// Rainbow tables for DES under a fixed plaintext. With -gen, a table is
+// generated; otherwise the cipher texts given in hex as arguments are
+// looked up in the table.
+func main() {
+	flag.StringVar(&tableFile, "table", "des.rbt", "rainbow table file")
+	flag.BoolVar(&generate, "gen", false, "generate the table")
+	flag.Var((*block.Hex)(&plain), "plain", "plaintext block in hex, when generating")
+	flag.IntVar(&chainLen, "chainlen", 10000, "keys per chain, when generating")
+	flag.IntVar(&chains, "chains", 1000000, "chains, when generating")
+	flag.Uint64Var(&start, "start", 0, "permuted key of the first chain, when generating")
+	flag.Parse()
+
+	log.SetOutput(os.Stdout)
+	if generate {
+		t0 := time.Now()
+		t := des.GenerateRainbowTable(plain, chainLen, chains, start)
+		log.Printf("Generated %d chains of %d keys in %v\n", t.Len(), t.ChainLen(), time.Since(t0))
+		f, err := os.Create(tableFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := t.WriteTo(f); err != nil {
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(tableFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	t, err := des.ReadRainbowTable(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, arg := range flag.Args() {
+		var out block.Hex
+		if err := out.Set(arg); err != nil {
+			log.Fatal(err)
+		}
+		t0 := time.Now()
+		if key, ok := t.Lookup(uint64(out)); ok {
+			log.Printf("%s: found key 0x%016x in %v\n", arg, key, time.Since(t0))
+		} else {
+			log.Printf("%s: key not found in %v\n", arg, time.Since(t0))
+		}
+	}
+}
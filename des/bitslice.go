@@ -0,0 +1,135 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import "math/bits"
+
+//go:generate go run gen_sbox.go
+
+// BitsliceCracker searches for DES keys 64 at a time by bitslicing:
+// bit i of every uint64 in the computation belongs to the i-th key of
+// a batch, so each boolean operation evaluates 64 keys at once. The
+// S-boxes are implemented as gate networks in sbox.go.
+type BitsliceCracker struct {
+	in  uint64
+	out [64]uint64 // bitsliced preoutput to match
+}
+
+// NewBitsliceCracker creates and returns a new BitsliceCracker.
+func NewBitsliceCracker(in, out uint64) *BitsliceCracker {
+	c := new(BitsliceCracker)
+	c.in = permuteInitialBlock(in)
+	out = permuteInitialBlock(out)
+	for i := range c.out {
+		c.out[i] = -(out >> uint(i) & 1)
+	}
+	return c
+}
+
+// SearchKey searches the permuted 56-bit keys in [min, max) and
+// returns the first key that encrypts to the cipher text, in the
+// format used by des.Cipher. It is equivalent to Cracker.SearchKey.
+func (c *BitsliceCracker) SearchKey(min, max uint64) (key uint64, ok bool) {
+	for base := min &^ 63; base < max; base += 64 {
+		lanes := ^uint64(0)
+		if base < min {
+			lanes <<= min - base
+		}
+		if max-base < 64 {
+			lanes &= 1<<(max-base) - 1
+		}
+		if match := c.checkKeys(base) & lanes; match != 0 {
+			permutedKey := base + uint64(bits.TrailingZeros64(match))
+			return permuteBlockInverse(permutedKey, permutedChoice1[:]), true
+		}
+	}
+	return 0, false
+}
+
+// checkKeys encrypts the block with the 64 permuted keys base through
+// base+63, where base is a multiple of 64, and returns a mask of the
+// keys that produce the cipher text.
+func (c *BitsliceCracker) checkKeys(base uint64) uint64 {
+	var k [56]uint64
+	copy(k[:6], bitsliceLanes[:])
+	for i := 6; i < 56; i++ {
+		k[i] = -(base >> uint(i) & 1)
+	}
+
+	var l, r [32]uint64
+	for i := 0; i < 32; i++ {
+		l[i] = -(c.in >> uint(32+i) & 1)
+		r[i] = -(c.in >> uint(i) & 1)
+	}
+
+	for i := 0; i < 16; i += 2 {
+		bitsliceRound(&l, &r, &k, &bitsliceSubkeys[i])
+		bitsliceRound(&r, &l, &k, &bitsliceSubkeys[i+1])
+	}
+
+	// compare with the preoutput, in which left & right are switched
+	var diff uint64
+	for i := 0; i < 32; i++ {
+		diff |= r[i] ^ c.out[32+i]
+	}
+	if diff == ^uint64(0) {
+		return 0
+	}
+	for i := 0; i < 32; i++ {
+		diff |= l[i] ^ c.out[i]
+	}
+	return ^diff
+}
+
+// bitsliceRound computes l ^= f(r, subkey) for one round, where l and
+// r are indexed by bit from least significant and subkey holds the
+// index in k of each subkey bit.
+func bitsliceRound(l, r *[32]uint64, k *[56]uint64, subkey *[48]uint8) {
+	var e [48]uint64
+	for i := range e {
+		e[i] = r[expansionFunction[i]] ^ k[subkey[i]]
+	}
+	var s [32]uint64
+	s[0], s[1], s[2], s[3] = sbox1(e[0], e[1], e[2], e[3], e[4], e[5])
+	s[4], s[5], s[6], s[7] = sbox2(e[6], e[7], e[8], e[9], e[10], e[11])
+	s[8], s[9], s[10], s[11] = sbox3(e[12], e[13], e[14], e[15], e[16], e[17])
+	s[12], s[13], s[14], s[15] = sbox4(e[18], e[19], e[20], e[21], e[22], e[23])
+	s[16], s[17], s[18], s[19] = sbox5(e[24], e[25], e[26], e[27], e[28], e[29])
+	s[20], s[21], s[22], s[23] = sbox6(e[30], e[31], e[32], e[33], e[34], e[35])
+	s[24], s[25], s[26], s[27] = sbox7(e[36], e[37], e[38], e[39], e[40], e[41])
+	s[28], s[29], s[30], s[31] = sbox8(e[42], e[43], e[44], e[45], e[46], e[47])
+	for i := range s {
+		l[31-i] ^= s[permutationFunction[i]^31]
+	}
+}
+
+// bitsliceLanes[i] has bit j set when bit i of j is set, so that the
+// low six bits of the permuted key vary across the 64 lanes.
+var bitsliceLanes = [6]uint64{
+	0xaaaaaaaaaaaaaaaa,
+	0xcccccccccccccccc,
+	0xf0f0f0f0f0f0f0f0,
+	0xff00ff00ff00ff00,
+	0xffff0000ffff0000,
+	0xffffffff00000000,
+}
+
+// bitsliceSubkeys[i][j] is the bit of the permuted key, from least
+// significant, that becomes bit j of subkey i, from most significant.
+// Since the key schedule only selects bits, it is a fixed mapping.
+var bitsliceSubkeys [16][48]uint8
+
+func init() {
+	rotation := 0
+	for i := range bitsliceSubkeys {
+		rotation += int(ksRotations[i])
+		for j, n := range permutedChoice2 {
+			// undo the 28-bit left rotation of the half containing bit n
+			half := n / 28 * 28
+			bit := (int(n-half) - rotation + 28) % 28
+			bitsliceSubkeys[i][j] = half + uint8(bit)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import "testing"
+
+func TestBitsliceSboxes(t *testing.T) {
+	sboxes := [8]func(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64){
+		sbox1, sbox2, sbox3, sbox4, sbox5, sbox6, sbox7, sbox8,
+	}
+	l := bitsliceLanes
+	for s, sbox := range sboxes {
+		// lane x evaluates input x
+		o1, o2, o3, o4 := sbox(l[5], l[4], l[3], l[2], l[1], l[0])
+		for x := uint(0); x < 64; x++ {
+			got := uint8(o1>>x&1<<3 | o2>>x&1<<2 | o3>>x&1<<1 | o4>>x&1)
+			want := sBoxes[s][x>>4&2|x&1][x>>1&0xf]
+			if got != want {
+				t.Errorf("S-box %d input %x: result: %x want: %x", s+1, x, got, want)
+			}
+		}
+	}
+}
+
+func TestBitsliceSearchKey(t *testing.T) {
+	for i, tt := range encryptDESTests {
+		c := NewBitsliceCracker(tt.in, tt.out)
+		permutedKey := permuteChoice1(tt.key)
+		min, max := uint64(0), permutedKey+100
+		if permutedKey > 100 {
+			min = permutedKey - 100
+		}
+
+		key, ok := c.SearchKey(min, max)
+		if !ok {
+			t.Errorf("#%d: key not found in range [%x, %x)", i, min, max)
+		}
+		if want := maskParity(tt.key); key != want {
+			t.Errorf("#%d: found key not equal: %x want %x", i, key, want)
+		}
+
+		for _, r := range [][2]uint64{
+			{permutedKey + 1, permutedKey + 100},
+			{min, permutedKey},
+			{permutedKey, permutedKey},
+		} {
+			if key, ok := c.SearchKey(r[0], r[1]); ok {
+				t.Errorf("#%d: key %x found in range [%x, %x)", i, key, r[0], r[1])
+			}
+		}
+	}
+}
+
+// Defined in Pub 800-20
+func TestBitsliceKnownAnswer(t *testing.T) {
+	var tests []DESTest
+	for _, tt := range tableA2Tests {
+		tests = append(tests, DESTest{tt.key[0], tableA2Plaintext, tt.out})
+	}
+	for _, tt := range tableA3Tests {
+		tests = append(tests, DESTest{tt.key[0], tableA3Plaintext, tt.out})
+	}
+	for _, tt := range tableA4Tests {
+		tests = append(tests, DESTest{tt.key[0], tt.in, tt.out})
+	}
+	for i, tt := range tests {
+		c := NewBitsliceCracker(tt.in, tt.out)
+		permutedKey := permuteChoice1(tt.key)
+		key, ok := c.SearchKey(permutedKey, permutedKey+1)
+		if !ok || key != maskParity(tt.key) {
+			t.Errorf("#%d: key %x: result: %x, %t want: %x", i, tt.key, key, ok, maskParity(tt.key))
+		}
+	}
+}
+
+func TestBitsliceMatchesCracker(t *testing.T) {
+	for i, tt := range encryptDESTests {
+		c := NewCracker(tt.in, tt.out)
+		bc := NewBitsliceCracker(tt.in, tt.out)
+		permutedKey := permuteChoice1(tt.key)
+		for _, offset := range []uint64{0, 1, 37, 63, 64, 65, 127} {
+			min := permutedKey - offset
+			if offset > permutedKey {
+				min = 0
+			}
+			for _, n := range []uint64{1, 63, 64, 200} {
+				max := min + n
+				key, ok := c.SearchKey(min, max)
+				bkey, bok := bc.SearchKey(min, max)
+				if key != bkey || ok != bok {
+					t.Errorf("#%d: range [%x, %x): result: %x, %t want: %x, %t", i, min, max, bkey, bok, key, ok)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkBitsliceSearchKey(b *testing.B) {
+	tt := encryptDESTests[19]
+	c := NewBitsliceCracker(tt.in, tt.out)
+	max := permuteChoice1(tt.key) + 100
+	min := max - 100000
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.SearchKey(min, max)
+	}
+}
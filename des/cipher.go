@@ -5,6 +5,11 @@
 
 package des
 
+import (
+	"crypto/cipher"
+	"encoding/binary"
+)
+
 // The DES block size in bytes.
 const BlockSize = 8
 
@@ -28,6 +33,12 @@ func (c *Cipher) DecryptBlock(block uint64) uint64 {
 	return c.cryptBlock(block, true)
 }
 
+// Block returns c as a cipher.Block operating on big-endian byte
+// blocks, for use with crypto/cipher modes.
+func (c *Cipher) Block() cipher.Block {
+	return blockAdapter{c}
+}
+
 func (c *Cipher) cryptBlock(block uint64, decrypt bool) (dst uint64) {
 	b := permuteInitialBlock(block)
 	left, right := uint32(b>>32), uint32(b)
@@ -67,6 +78,12 @@ func NewTripleDESCipher(key [3]uint64) *TripleDESCipher {
 	return c
 }
 
+// Block returns c as a cipher.Block operating on big-endian byte
+// blocks, for use with crypto/cipher modes.
+func (c *TripleDESCipher) Block() cipher.Block {
+	return blockAdapter{c}
+}
+
 func (c *TripleDESCipher) EncryptBlock(block uint64) uint64 {
 	b := permuteInitialBlock(block)
 	left, right := uint32(b>>32), uint32(b)
@@ -114,3 +131,34 @@ func (c *TripleDESCipher) DecryptBlock(block uint64) uint64 {
 	preOutput := (uint64(right) << 32) | uint64(left)
 	return permuteFinalBlock(preOutput)
 }
+
+type uint64Block interface {
+	EncryptBlock(block uint64) uint64
+	DecryptBlock(block uint64) uint64
+}
+
+// blockAdapter implements cipher.Block for a cipher on uint64 blocks.
+type blockAdapter struct {
+	b uint64Block
+}
+
+func (a blockAdapter) BlockSize() int { return BlockSize }
+
+func (a blockAdapter) Encrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	binary.BigEndian.PutUint64(dst, a.b.EncryptBlock(binary.BigEndian.Uint64(src)))
+}
+
+func (a blockAdapter) Decrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	binary.BigEndian.PutUint64(dst, a.b.DecryptBlock(binary.BigEndian.Uint64(src)))
+}
+
+func checkBlock(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("des: input not full block")
+	}
+	if len(dst) < BlockSize {
+		panic("des: output not full block")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bytes"
+	"crypto/cipher"
+	stddes "crypto/des"
+	"encoding/binary"
+	"testing"
+)
+
+func blockBytes(x uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, x)
+	return b
+}
+
+func checkBlockAdapter(t *testing.T, i int, c, std cipher.Block, in, out uint64) {
+	t.Helper()
+	if c.BlockSize() != std.BlockSize() {
+		t.Errorf("#%d: block size: %d want: %d", i, c.BlockSize(), std.BlockSize())
+	}
+	src, want := blockBytes(in), blockBytes(out)
+	dst, stdDst := make([]byte, 8), make([]byte, 8)
+	c.Encrypt(dst, src)
+	std.Encrypt(stdDst, src)
+	if !bytes.Equal(dst, want) || !bytes.Equal(stdDst, want) {
+		t.Errorf("#%d: encrypt: %x, crypto/des: %x want: %x", i, dst, stdDst, want)
+	}
+	c.Decrypt(dst, want)
+	std.Decrypt(stdDst, want)
+	if !bytes.Equal(dst, src) || !bytes.Equal(stdDst, src) {
+		t.Errorf("#%d: decrypt: %x, crypto/des: %x want: %x", i, dst, stdDst, src)
+	}
+}
+
+func TestCipherBlock(t *testing.T) {
+	for i, tt := range encryptDESTests {
+		std, err := stddes.NewCipher(blockBytes(tt.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkBlockAdapter(t, i, NewCipher(tt.key).Block(), std, tt.in, tt.out)
+	}
+}
+
+func TestTripleDESCipherBlock(t *testing.T) {
+	tests := append([]TripleDESTest(nil), encryptTripleDESTests...)
+	for _, tt := range tableA1Tests {
+		tests = append(tests, TripleDESTest{tableA1Key, tt.in, tt.out})
+	}
+	tests = append(tests, tableA4Tests...)
+	for i, tt := range tests {
+		key := append(append(blockBytes(tt.key[0]), blockBytes(tt.key[1])...), blockBytes(tt.key[2])...)
+		std, err := stddes.NewTripleDESCipher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkBlockAdapter(t, i, NewTripleDESCipher(tt.key).Block(), std, tt.in, tt.out)
+	}
+}
+
+func TestCipherBlockMode(t *testing.T) {
+	tt := encryptDESTests[19]
+	std, err := stddes.NewCipher(blockBytes(tt.key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := blockBytes(0x0123456789abcdef)
+	src := []byte("the quick brown fox jumps over!!")
+	dst, want := make([]byte, len(src)), make([]byte, len(src))
+	cipher.NewCBCEncrypter(NewCipher(tt.key).Block(), iv).CryptBlocks(dst, src)
+	cipher.NewCBCEncrypter(std, iv).CryptBlocks(want, src)
+	if !bytes.Equal(dst, want) {
+		t.Errorf("CBC: %x want: %x", dst, want)
+	}
+}
@@ -21,21 +21,50 @@ func NewCracker(in, out uint64) *Cracker {
 // CheckKey checks whether the given permuted 56-bit key encrypts to the
 // cipher text and returns the key in the format used by des.Cipher.
 func (c *Cracker) CheckKey(permutedKey uint64) (key uint64, ok bool) {
+	if encryptPermutedKey(c.in, permutedKey) == c.out {
+		// apply PC1 permutation to key in reverse
+		key = permuteBlockInverse(permutedKey, permutedChoice1[:])
+		return key, true
+	}
+	return 0, false
+}
+
+// PermuteKey applies the PC1 permutation to a key in the format used
+// by des.Cipher, dropping its parity bits, and returns the permuted
+// 56-bit key used by CheckKey and SearchKey.
+func PermuteKey(key uint64) uint64 {
+	return permuteChoice1(key)
+}
+
+// encryptPermutedKey encrypts a block that has already had the initial
+// permutation applied with a permuted key and returns the preoutput.
+func encryptPermutedKey(in, permutedKey uint64) uint64 {
+	subkeys := permutedSubkeys(permutedKey)
+	return encryptPermuted(in, &subkeys)
+}
+
+// permutedSubkeys creates 16 subkeys from a key that has already had
+// the PC1 permutation applied.
+func permutedSubkeys(permutedKey uint64) (subkeys [16]uint64) {
 	// rotate halves of key according to the rotation schedule
 	leftRotations := ksRotate(uint32(permutedKey >> 28))
 	rightRotations := ksRotate(uint32(permutedKey<<4) >> 4)
 
 	// generate subkeys
-	var subkeys [16]uint64
 	for i := 0; i < 16; i++ {
 		// combine halves to form 56-bit input to PC2
 		pc2Input := uint64(leftRotations[i])<<28 | uint64(rightRotations[i])
 		// apply PC2 permutation to 7 byte input
 		subkeys[i] = unpack(permuteChoice2(pc2Input))
 	}
+	return
+}
 
-	// decrypt
-	left, right := uint32(c.in>>32), uint32(c.in)
+// encryptPermuted encrypts a block that has already had the initial
+// permutation applied and returns the preoutput, without the final
+// permutation.
+func encryptPermuted(in uint64, subkeys *[16]uint64) uint64 {
+	left, right := uint32(in>>32), uint32(in)
 
 	left = (left << 1) | (left >> 31)
 	right = (right << 1) | (right >> 31)
@@ -48,14 +77,7 @@ func (c *Cracker) CheckKey(permutedKey uint64) (key uint64, ok bool) {
 	right = (right << 31) | (right >> 1)
 
 	// switch left & right
-	preOutput := (uint64(right) << 32) | uint64(left)
-
-	if preOutput == c.out {
-		// apply PC1 permutation to key in reverse
-		key = permuteBlockInverse(permutedKey, permutedChoice1[:])
-		return key, true
-	}
-	return 0, false
+	return (uint64(right) << 32) | uint64(left)
 }
 
 func (c *Cracker) SearchKey(min, max uint64) (key uint64, ok bool) {
@@ -0,0 +1,128 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"encoding/binary"
+
+	"github.com/andrewarchi/adobe-cred/rules"
+)
+
+// KeyFromWord converts a word into a key in the format used by
+// des.Cipher. The word is null padded or truncated to 8 bytes and the
+// parity bits, which DES ignores, are cleared, so that words differing
+// only in parity give the same key.
+func KeyFromWord(word []byte) uint64 {
+	var b [8]byte
+	copy(b[:], word)
+	return binary.BigEndian.Uint64(b[:]) &^ 0x0101010101010101
+}
+
+// Mangle selects the mangling rules applied to dictionary words.
+type Mangle uint
+
+const (
+	MangleCase   Mangle = 1 << iota // lowercase, uppercase, and title case
+	MangleLeet                      // letter to digit and symbol substitutions
+	MangleDigits                    // append 0 through 9 and 00 through 99
+
+	MangleAll = MangleCase | MangleLeet | MangleDigits
+)
+
+var mangleRules [MangleAll + 1]rules.Rules
+
+func init() {
+	for m := range mangleRules {
+		var sets [3]rules.Rules
+		for i, set := range []rules.Rules{rules.Case, rules.Leet, rules.Digits} {
+			if Mangle(m)&(1<<i) != 0 {
+				sets[i] = set
+			}
+		}
+		mangleRules[m] = rules.Product(sets[:]...)
+	}
+}
+
+// Rules returns the selected mangling rules as the product of the
+// built-in rule sets of package rules.
+func (m Mangle) Rules() rules.Rules {
+	return mangleRules[m&MangleAll]
+}
+
+// MangleWord calls fn with the word and each variant of it formed by
+// the mangling rules, stopping early if fn returns false. Variants may
+// repeat and the slice passed to fn is reused between calls.
+func MangleWord(word []byte, m Mangle, fn func(variant []byte) bool) bool {
+	for it := m.Rules().Apply(word); it.Next(); {
+		if !fn(it.Word()) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckWord checks the keys formed from a word and its mangled
+// variants and returns the first key that encrypts to the cipher text,
+// in the format used by des.Cipher, with the variant that formed it.
+// It also returns the number of distinct keys checked.
+func (c *Cracker) CheckWord(word []byte, m Mangle) (key uint64, variant []byte, n int, ok bool) {
+	key, variant, n, ok = c.checkRules(word, m.Rules(), make(map[uint64]bool))
+	if ok {
+		variant = append([]byte(nil), variant...)
+	}
+	return key, variant, n, ok
+}
+
+// WordScanner yields dictionary words, in the style of bufio.Scanner,
+// which implements it.
+type WordScanner interface {
+	Scan() bool
+	Bytes() []byte
+}
+
+// WordMatch is a key formed from a dictionary word.
+type WordMatch struct {
+	Key     uint64 // in the format used by des.Cipher
+	Word    []byte // dictionary word
+	Variant []byte // word after applying a rule
+}
+
+// CheckWords checks the keys formed by applying each rule to each word
+// and returns the first key that encrypts to the cipher text. Empty
+// rules check the words unchanged. It also returns the number of keys
+// checked, which are distinct for each word.
+func (c *Cracker) CheckWords(words WordScanner, rs rules.Rules) (m WordMatch, n int, ok bool) {
+	seen := make(map[uint64]bool)
+	for words.Scan() {
+		word := words.Bytes()
+		key, variant, k, ok := c.checkRules(word, rs, seen)
+		n += k
+		if ok {
+			m = WordMatch{key, append([]byte(nil), word...), append([]byte(nil), variant...)}
+			return m, n, true
+		}
+	}
+	return WordMatch{}, n, false
+}
+
+// checkRules checks the distinct keys formed by applying the rules to a
+// word. The returned variant is only valid until the next call.
+func (c *Cracker) checkRules(word []byte, rs rules.Rules, seen map[uint64]bool) (key uint64, variant []byte, n int, ok bool) {
+	for k := range seen {
+		delete(seen, k)
+	}
+	for it := rs.Apply(word); it.Next(); {
+		k := KeyFromWord(it.Word())
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		n++
+		if key, ok = c.CheckKey(permuteChoice1(k)); ok {
+			return key, it.Word(), n, true
+		}
+	}
+	return 0, nil, n, false
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/andrewarchi/adobe-cred/rules"
+)
+
+func TestKeyFromWord(t *testing.T) {
+	tests := []struct {
+		word string
+		key  uint64
+	}{
+		{"password", 0x70607272766e7264},
+		{"passwordlonger", 0x70607272766e7264},
+		{"q`rrvnse", 0x70607272766e7264}, // differs only in parity
+		{"cat", 0x6260740000000000},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if key := KeyFromWord([]byte(tt.word)); key != tt.key {
+			t.Errorf("%q: result: %x want: %x", tt.word, key, tt.key)
+		}
+	}
+}
+
+func TestMangleWord(t *testing.T) {
+	tests := []struct {
+		word string
+		m    Mangle
+		want []string
+	}{
+		{"Pass", 0, []string{"Pass"}},
+		{"Pass", MangleCase, []string{"pass", "PASS", "Pass"}},
+		{"Pass", MangleLeet, []string{"P455", "P@$$"}},
+		{"Pass", MangleDigits, []string{"Pass0", "Pass9", "Pass00", "Pass42", "Pass99"}},
+		{"Pass", MangleAll, []string{"p455", "P455", "p@$$1", "P45512"}},
+	}
+	for _, tt := range tests {
+		variants := make(map[string]bool)
+		MangleWord([]byte(tt.word), tt.m, func(v []byte) bool {
+			variants[string(v)] = true
+			return true
+		})
+		for _, w := range append(tt.want, tt.word) {
+			if !variants[w] {
+				t.Errorf("%q %d: missing variant %q", tt.word, tt.m, w)
+			}
+		}
+		if tt.m == 0 && len(variants) != 1 {
+			t.Errorf("%q: variants without mangling: %v", tt.word, variants)
+		}
+	}
+
+	n := 0
+	MangleWord([]byte("word"), MangleAll, func(v []byte) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Errorf("stopped after %d variants want 3", n)
+	}
+}
+
+func TestCheckWord(t *testing.T) {
+	in := uint64(0x70617373776f7264) // "password"
+	for _, tt := range []struct {
+		word, key string
+		m         Mangle
+	}{
+		{"dragon", "dragon", 0},
+		{"dragon", "Dragon12", MangleCase | MangleDigits},
+		{"monkey", "m0nk3y", MangleLeet},
+		{"sunshine", "SUNSHINE", MangleCase},
+	} {
+		out := NewCipher(KeyFromWord([]byte(tt.key))).EncryptBlock(in)
+		c := NewCracker(in, out)
+		key, variant, n, ok := c.CheckWord([]byte(tt.word), tt.m)
+		if !ok || key != KeyFromWord([]byte(tt.key)) || KeyFromWord(variant) != key {
+			t.Errorf("%q: result: %x, %q, %t want: %x", tt.key, key, variant, ok, KeyFromWord([]byte(tt.key)))
+		}
+		if n == 0 {
+			t.Errorf("%q: no keys checked", tt.key)
+		}
+		if _, _, _, ok := c.CheckWord([]byte("letmein"), tt.m); ok {
+			t.Errorf("%q: matched wrong word", tt.key)
+		}
+	}
+}
+
+func TestCheckWords(t *testing.T) {
+	in := uint64(0x70617373776f7264) // "password"
+	out := NewCipher(KeyFromWord([]byte("M0nk3y42"))).EncryptBlock(in)
+	c := NewCracker(in, out)
+	words := "dragon\nsunshine\nmonkey\nletmein\n"
+
+	m, n, ok := c.CheckWords(bufio.NewScanner(strings.NewReader(words)), MangleAll.Rules())
+	if !ok || m.Key != KeyFromWord([]byte("M0nk3y42")) || string(m.Word) != "monkey" || KeyFromWord(m.Variant) != m.Key {
+		t.Errorf("result: %x, %q, %q, %t", m.Key, m.Word, m.Variant, ok)
+	}
+	if n == 0 {
+		t.Error("no keys checked")
+	}
+
+	rs := rules.Rules{rules.MustParse("c so0 se3 $4 $2")}
+	if m, n, ok := c.CheckWords(bufio.NewScanner(strings.NewReader(words)), rs); !ok || string(m.Variant) != "M0nk3y42" || n != 3 {
+		t.Errorf("with rule: %q, %d keys, %t", m.Variant, n, ok)
+	}
+	if _, n, ok := c.CheckWords(bufio.NewScanner(strings.NewReader(words)), nil); ok || n != 4 {
+		t.Errorf("without rules: %d keys, %t", n, ok)
+	}
+}
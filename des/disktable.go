@@ -0,0 +1,186 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// diskTable is a table of (value, key) entries sorted by value and
+// stored on disk, for tables too large for memory. Entries are sorted
+// in memory in runs, which are spilled to files and merged when the
+// table is finished.
+type diskTable struct {
+	dir    string
+	mem    []tableEntry
+	memCap int
+	runs   []string
+	f      *os.File // merged table, once finished
+	n      int64
+}
+
+type tableEntry struct {
+	value, key uint64
+}
+
+const tableEntrySize = 16
+
+func newDiskTable(dir string, memEntries int) (*diskTable, error) {
+	dir, err := os.MkdirTemp(dir, "des-table-")
+	if err != nil {
+		return nil, err
+	}
+	return &diskTable{dir: dir, memCap: memEntries}, nil
+}
+
+// add inserts an entry, spilling a sorted run when memory is full.
+func (t *diskTable) add(value, key uint64) error {
+	t.mem = append(t.mem, tableEntry{value, key})
+	if len(t.mem) >= t.memCap {
+		return t.spill()
+	}
+	return nil
+}
+
+func (t *diskTable) spill() error {
+	sort.Slice(t.mem, func(i, j int) bool { return t.mem[i].value < t.mem[j].value })
+	name := filepath.Join(t.dir, "run"+strconv.Itoa(len(t.runs)))
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range t.mem {
+		if err := writeEntry(w, e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	t.runs = append(t.runs, name)
+	t.mem = t.mem[:0]
+	return f.Close()
+}
+
+// finish merges the runs into a single sorted file for lookups.
+func (t *diskTable) finish() error {
+	if len(t.mem) != 0 || len(t.runs) == 0 {
+		if err := t.spill(); err != nil {
+			return err
+		}
+	}
+	t.mem = nil
+
+	readers := make([]*bufio.Reader, len(t.runs))
+	heads := make([]tableEntry, len(t.runs))
+	live := make([]bool, len(t.runs))
+	for i, name := range t.runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers[i] = bufio.NewReader(f)
+		heads[i], err = readEntry(readers[i])
+		live[i] = err == nil
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+	f, err := os.Create(filepath.Join(t.dir, "table"))
+	if err != nil {
+		return err
+	}
+	t.f = f
+	w := bufio.NewWriter(f)
+	for {
+		// runs are few, so a linear scan for the least head suffices
+		min := -1
+		for i := range heads {
+			if live[i] && (min == -1 || heads[i].value < heads[min].value) {
+				min = i
+			}
+		}
+		if min == -1 {
+			break
+		}
+		if err := writeEntry(w, heads[min]); err != nil {
+			return err
+		}
+		t.n++
+		heads[min], err = readEntry(readers[min])
+		if err == io.EOF {
+			live[min] = false
+		} else if err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, name := range t.runs {
+		os.Remove(name)
+	}
+	t.runs = nil
+	return nil
+}
+
+// lookup returns the keys of the entries with a value.
+func (t *diskTable) lookup(value uint64) ([]uint64, error) {
+	var err error
+	at := func(i int64) tableEntry {
+		var b [tableEntrySize]byte
+		if _, rerr := t.f.ReadAt(b[:], i*tableEntrySize); rerr != nil && err == nil {
+			err = rerr
+		}
+		return tableEntry{binary.LittleEndian.Uint64(b[:]), binary.LittleEndian.Uint64(b[8:])}
+	}
+	i := int64(sort.Search(int(t.n), func(i int) bool { return err != nil || at(int64(i)).value >= value }))
+	var keys []uint64
+	for ; i < t.n && err == nil; i++ {
+		e := at(i)
+		if e.value != value {
+			break
+		}
+		keys = append(keys, e.key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Close removes the table from disk.
+func (t *diskTable) Close() error {
+	if t.f != nil {
+		t.f.Close()
+	}
+	return os.RemoveAll(t.dir)
+}
+
+func writeEntry(w io.Writer, e tableEntry) error {
+	var b [tableEntrySize]byte
+	binary.LittleEndian.PutUint64(b[:], e.value)
+	binary.LittleEndian.PutUint64(b[8:], e.key)
+	_, err := w.Write(b[:])
+	return err
+}
+
+func readEntry(r io.Reader) (tableEntry, error) {
+	var b [tableEntrySize]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return tableEntry{}, err
+	}
+	return tableEntry{binary.LittleEndian.Uint64(b[:]), binary.LittleEndian.Uint64(b[8:])}, nil
+}
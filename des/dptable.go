@@ -0,0 +1,244 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DPTable is a Hellman table with distinguished points for recovering
+// the key that encrypts a fixed plaintext block. Each chain repeatedly
+// encrypts the plaintext with a permuted 56-bit key and reduces the
+// preoutput to the next key with a single reduction function, ending at
+// the first distinguished point, a key whose low bits are zero. Chains
+// that exceed the maximum length are discarded. Since chains that merge
+// share their end, they are deduplicated by keeping the longest.
+//
+// Tables can be grown across runs, since the table records the start
+// of the next chain to generate.
+type DPTable struct {
+	in      uint64 // permuted plaintext
+	variant uint64 // reduction function
+	dpBits  uint
+	maxLen  int
+	next    uint64 // start of the next chain
+	chains  []dpChain
+}
+
+type dpChain struct {
+	start, end uint64 // permuted 56-bit keys
+	length     uint32 // number of keys before the end
+}
+
+// NewDPTable constructs an empty table for the plaintext block, in
+// which distinguished points have dpBits low zero bits and chains have
+// at most maxLen keys. Tables with different variants use different
+// reduction functions, so their chains are independent.
+func NewDPTable(in uint64, dpBits uint, maxLen int, variant uint64) *DPTable {
+	return &DPTable{
+		in:      permuteInitialBlock(in),
+		variant: variant & keyMask,
+		dpBits:  dpBits,
+		maxLen:  maxLen,
+	}
+}
+
+func (t *DPTable) reduce(out uint64) uint64 {
+	return (out ^ t.variant) & keyMask
+}
+
+func (t *DPTable) distinguished(key uint64) bool {
+	return key&(1<<t.dpBits-1) == 0
+}
+
+// chain walks from a key to a distinguished point and returns the end
+// and the number of keys before it, or false if the maximum length is
+// exceeded.
+func (t *DPTable) chain(key uint64) (end uint64, length int, ok bool) {
+	for length = 0; length < t.maxLen; length++ {
+		if t.distinguished(key) {
+			return key, length, true
+		}
+		key = t.reduce(encryptPermutedKey(t.in, key))
+	}
+	return 0, 0, false
+}
+
+// Generate attempts n more chains on all CPUs, continuing from the
+// chains generated by previous calls, and returns the number of chains
+// added to the table.
+func (t *DPTable) Generate(n int) int {
+	start := t.next
+	t.next += uint64(n)
+	workers := runtime.GOMAXPROCS(-1)
+	results := make([][]dpChain, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				key := (start + uint64(i)) & keyMask
+				if end, length, ok := t.chain(key); ok {
+					results[w] = append(results[w], dpChain{key, end, uint32(length)})
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	before := len(t.chains)
+	for _, r := range results {
+		t.chains = append(t.chains, r...)
+	}
+	t.merge()
+	return len(t.chains) - before
+}
+
+// merge sorts the chains by end and keeps the longest chain for each
+// end.
+func (t *DPTable) merge() {
+	sort.Slice(t.chains, func(i, j int) bool {
+		a, b := t.chains[i], t.chains[j]
+		if a.end != b.end {
+			return a.end < b.end
+		}
+		if a.length != b.length {
+			return a.length > b.length
+		}
+		return a.start < b.start
+	})
+	chains := t.chains[:0]
+	for i, c := range t.chains {
+		if i == 0 || c.end != t.chains[i-1].end {
+			chains = append(chains, c)
+		}
+	}
+	t.chains = chains
+}
+
+// Len returns the number of chains in the table.
+func (t *DPTable) Len() int {
+	return len(t.chains)
+}
+
+// Keys returns the number of keys covered by the chains, counting keys
+// shared by merging chains more than once.
+func (t *DPTable) Keys() uint64 {
+	var n uint64
+	for _, c := range t.chains {
+		n += uint64(c.length)
+	}
+	return n
+}
+
+// Lookup searches the table for a key that encrypts the plaintext to
+// the cipher text and returns it in the format used by des.Cipher.
+func (t *DPTable) Lookup(out uint64) (key uint64, ok bool) {
+	out = permuteInitialBlock(out)
+	end, _, ok := t.chain(t.reduce(out))
+	if !ok {
+		return 0, false
+	}
+	i := sort.Search(len(t.chains), func(i int) bool { return t.chains[i].end >= end })
+	if i == len(t.chains) || t.chains[i].end != end {
+		return 0, false
+	}
+	// the target may not be on the stored chain if it merged with it
+	c := t.chains[i]
+	k := c.start
+	for j := uint32(0); j < c.length; j++ {
+		next := encryptPermutedKey(t.in, k)
+		if next == out {
+			return permuteBlockInverse(k, permutedChoice1[:]), true
+		}
+		k = t.reduce(next)
+	}
+	return 0, false
+}
+
+var dpMagic = [8]byte{'D', 'E', 'S', 'D', 'P', 'T', '0', '1'}
+
+// WriteTo writes the table in a compact binary format: a header of
+// magic, permuted plaintext, variant, distinguished bits, maximum
+// length, next start, and chain count, followed by the 7-byte start
+// and end keys and 4-byte length of each chain, all little endian.
+func (t *DPTable) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var h [56]byte
+	copy(h[:], dpMagic[:])
+	for i, x := range []uint64{t.in, t.variant, uint64(t.dpBits), uint64(t.maxLen), t.next, uint64(len(t.chains))} {
+		binary.LittleEndian.PutUint64(h[8+8*i:], x)
+	}
+	n, err := bw.Write(h[:])
+	written := int64(n)
+	var b [24]byte
+	for _, c := range t.chains {
+		if err != nil {
+			return written, err
+		}
+		binary.LittleEndian.PutUint64(b[:], c.start)
+		binary.LittleEndian.PutUint64(b[7:], c.end)
+		binary.LittleEndian.PutUint32(b[14:], c.length)
+		n, err = bw.Write(b[:18])
+		written += int64(n)
+	}
+	if err != nil {
+		return written, err
+	}
+	return written, bw.Flush()
+}
+
+// ReadDPTable reads a table written by WriteTo.
+func ReadDPTable(r io.Reader) (*DPTable, error) {
+	br := bufio.NewReader(r)
+	var h [56]byte
+	if _, err := io.ReadFull(br, h[:]); err != nil {
+		return nil, err
+	}
+	if string(h[:8]) != string(dpMagic[:]) {
+		return nil, errors.New("des: not a distinguished point table")
+	}
+	var x [6]uint64
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint64(h[8+8*i:])
+	}
+	if x[2] > 56 {
+		return nil, fmt.Errorf("des: distinguished point table has %d distinguished bits", x[2])
+	}
+	if x[3] == 0 || x[3] > maxChainLen {
+		return nil, fmt.Errorf("des: distinguished point table maximum length %d out of range", x[3])
+	}
+	t := &DPTable{
+		in:      x[0],
+		variant: x[1],
+		dpBits:  uint(x[2]),
+		maxLen:  int(x[3]),
+		next:    x[4],
+	}
+	t.chains = make([]dpChain, 0, preallocChains(x[5]))
+	var b [24]byte
+	for i := uint64(0); i < x[5]; i++ {
+		if _, err := io.ReadFull(br, b[:18]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("des: distinguished point table chain %d: %v", i, err)
+		}
+		t.chains = append(t.chains, dpChain{
+			binary.LittleEndian.Uint64(b[:]) & keyMask,
+			binary.LittleEndian.Uint64(b[7:]) & keyMask,
+			binary.LittleEndian.Uint32(b[14:]),
+		})
+	}
+	return t, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestDPTable(t *testing.T) {
+	const in = 0x70617373776f7264 // "password"
+	dt := NewDPTable(in, 5, 200, 0xabcdef)
+	added := dt.Generate(300)
+	if added == 0 || dt.Len() != added {
+		t.Fatalf("added %d chains, table has %d", added, dt.Len())
+	}
+	for i := 1; i < dt.Len(); i++ {
+		if dt.chains[i-1].end >= dt.chains[i].end {
+			t.Fatalf("chains %d and %d not sorted and unique", i-1, i)
+		}
+	}
+
+	// grow the table and check that earlier chains are kept
+	first := append([]dpChain(nil), dt.chains...)
+	dt.Generate(300)
+	if dt.next != 600 {
+		t.Errorf("next start: %d want 600", dt.next)
+	}
+	ends := make(map[uint64]uint32)
+	for _, c := range dt.chains {
+		ends[c.end] = c.length
+	}
+	for _, c := range first {
+		if length, ok := ends[c.end]; !ok || length < c.length {
+			t.Errorf("chain ending at %x lost after growing", c.end)
+		}
+	}
+
+	// keys on stored chains are found
+	found := 0
+	for _, c := range dt.chains[:20] {
+		k := c.start
+		for j := uint32(0); j < c.length; j++ {
+			out := NewCipher(permuteBlockInverse(k, permutedChoice1[:])).EncryptBlock(in)
+			key, ok := dt.Lookup(out)
+			if !ok {
+				t.Errorf("key %x at %d of chain from %x not found", k, j, c.start)
+			} else if got := NewCipher(key).EncryptBlock(in); got != out {
+				t.Errorf("key %x encrypts to %x want %x", key, got, out)
+			} else {
+				found++
+			}
+			k = dt.reduce(encryptPermutedKey(dt.in, k))
+		}
+	}
+	if found == 0 {
+		t.Error("no keys found")
+	}
+
+	var buf bytes.Buffer
+	if _, err := dt.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if want := 56 + 18*dt.Len(); len(data) != want {
+		t.Errorf("table size: %d want %d", len(data), want)
+	}
+	dt2, err := ReadDPTable(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dt2.in != dt.in || dt2.variant != dt.variant || dt2.dpBits != dt.dpBits ||
+		dt2.maxLen != dt.maxLen || dt2.next != dt.next || len(dt2.chains) != len(dt.chains) {
+		t.Fatalf("read table differs")
+	}
+	for i := range dt.chains {
+		if dt2.chains[i] != dt.chains[i] {
+			t.Errorf("chain %d: %x want %x", i, dt2.chains[i], dt.chains[i])
+		}
+	}
+	if _, err := ReadDPTable(bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Error("truncated table read without error")
+	}
+}
+
+func TestReadDPTableCorrupt(t *testing.T) {
+	dt := NewDPTable(0x0123456789abcdef, 4, 64, 0)
+	dt.Generate(8)
+	var buf bytes.Buffer
+	if _, err := dt.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	for _, tt := range []struct {
+		off int
+		x   uint64
+	}{
+		{24, 57},      // distinguished bits
+		{32, 0},       // maximum length
+		{32, 1 << 40}, // maximum length
+		{48, 1 << 60}, // chain count beyond the data
+	} {
+		corrupt := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(corrupt[tt.off:], tt.x)
+		if _, err := ReadDPTable(bytes.NewReader(corrupt)); err == nil {
+			t.Errorf("header field %d = %d: no error", tt.off, tt.x)
+		}
+	}
+}
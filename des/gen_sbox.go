@@ -0,0 +1,241 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// gen_sbox generates boolean gate networks for the DES S-boxes for use
+// by BitsliceCracker. Each output bit is synthesized by Shannon
+// expansion over the six input bits with sub-functions shared between
+// the four outputs of an S-box. Every variable ordering is tried and
+// the network with the fewest gates is kept.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+)
+
+// Copy of sBoxes from const.go, which cannot be imported from an
+// ignored file.
+var sBoxes = [8][4][16]uint8{
+	{
+		{14, 4, 13, 1, 2, 15, 11, 8, 3, 10, 6, 12, 5, 9, 0, 7},
+		{0, 15, 7, 4, 14, 2, 13, 1, 10, 6, 12, 11, 9, 5, 3, 8},
+		{4, 1, 14, 8, 13, 6, 2, 11, 15, 12, 9, 7, 3, 10, 5, 0},
+		{15, 12, 8, 2, 4, 9, 1, 7, 5, 11, 3, 14, 10, 0, 6, 13},
+	},
+	{
+		{15, 1, 8, 14, 6, 11, 3, 4, 9, 7, 2, 13, 12, 0, 5, 10},
+		{3, 13, 4, 7, 15, 2, 8, 14, 12, 0, 1, 10, 6, 9, 11, 5},
+		{0, 14, 7, 11, 10, 4, 13, 1, 5, 8, 12, 6, 9, 3, 2, 15},
+		{13, 8, 10, 1, 3, 15, 4, 2, 11, 6, 7, 12, 0, 5, 14, 9},
+	},
+	{
+		{10, 0, 9, 14, 6, 3, 15, 5, 1, 13, 12, 7, 11, 4, 2, 8},
+		{13, 7, 0, 9, 3, 4, 6, 10, 2, 8, 5, 14, 12, 11, 15, 1},
+		{13, 6, 4, 9, 8, 15, 3, 0, 11, 1, 2, 12, 5, 10, 14, 7},
+		{1, 10, 13, 0, 6, 9, 8, 7, 4, 15, 14, 3, 11, 5, 2, 12},
+	},
+	{
+		{7, 13, 14, 3, 0, 6, 9, 10, 1, 2, 8, 5, 11, 12, 4, 15},
+		{13, 8, 11, 5, 6, 15, 0, 3, 4, 7, 2, 12, 1, 10, 14, 9},
+		{10, 6, 9, 0, 12, 11, 7, 13, 15, 1, 3, 14, 5, 2, 8, 4},
+		{3, 15, 0, 6, 10, 1, 13, 8, 9, 4, 5, 11, 12, 7, 2, 14},
+	},
+	{
+		{2, 12, 4, 1, 7, 10, 11, 6, 8, 5, 3, 15, 13, 0, 14, 9},
+		{14, 11, 2, 12, 4, 7, 13, 1, 5, 0, 15, 10, 3, 9, 8, 6},
+		{4, 2, 1, 11, 10, 13, 7, 8, 15, 9, 12, 5, 6, 3, 0, 14},
+		{11, 8, 12, 7, 1, 14, 2, 13, 6, 15, 0, 9, 10, 4, 5, 3},
+	},
+	{
+		{12, 1, 10, 15, 9, 2, 6, 8, 0, 13, 3, 4, 14, 7, 5, 11},
+		{10, 15, 4, 2, 7, 12, 9, 5, 6, 1, 13, 14, 0, 11, 3, 8},
+		{9, 14, 15, 5, 2, 8, 12, 3, 7, 0, 4, 10, 1, 13, 11, 6},
+		{4, 3, 2, 12, 9, 5, 15, 10, 11, 14, 1, 7, 6, 0, 8, 13},
+	},
+	{
+		{4, 11, 2, 14, 15, 0, 8, 13, 3, 12, 9, 7, 5, 10, 6, 1},
+		{13, 0, 11, 7, 4, 9, 1, 10, 14, 3, 5, 12, 2, 15, 8, 6},
+		{1, 4, 11, 13, 12, 3, 7, 14, 10, 15, 6, 8, 0, 5, 9, 2},
+		{6, 11, 13, 8, 1, 4, 10, 7, 9, 5, 0, 15, 14, 2, 3, 12},
+	},
+	{
+		{13, 2, 8, 4, 6, 15, 11, 1, 10, 9, 3, 14, 5, 0, 12, 7},
+		{1, 15, 13, 8, 10, 3, 7, 4, 12, 5, 6, 11, 0, 14, 9, 2},
+		{7, 11, 4, 1, 9, 12, 14, 2, 0, 6, 10, 13, 15, 3, 5, 8},
+		{2, 1, 14, 7, 4, 10, 8, 13, 15, 12, 9, 0, 3, 5, 6, 11},
+	},
+}
+
+// A truth table over the six S-box inputs. Bit x holds the function's
+// value for the input x, where a1 is the most significant bit of x.
+type truthTable = uint64
+
+const allOnes truthTable = ^truthTable(0)
+
+// proj[i] is the truth table of input a(i+1).
+var proj [6]truthTable
+
+func init() {
+	for i := range proj {
+		for x := uint(0); x < 64; x++ {
+			if x>>(5-uint(i))&1 != 0 {
+				proj[i] |= 1 << x
+			}
+		}
+	}
+}
+
+// cofactor returns f with input i fixed to v.
+func cofactor(f truthTable, i int, v bool) truthTable {
+	bit := uint(5 - i)
+	var g truthTable
+	for x := uint(0); x < 64; x++ {
+		y := x &^ (1 << bit)
+		if v {
+			y |= 1 << bit
+		}
+		g |= (f >> y & 1) << x
+	}
+	return g
+}
+
+func dependsOn(f truthTable, i int) bool {
+	return cofactor(f, i, false) != cofactor(f, i, true)
+}
+
+// network accumulates gates shared between the outputs of one S-box.
+type network struct {
+	order [6]int
+	names map[truthTable]string
+	body  bytes.Buffer
+	gates int
+	temps int
+}
+
+func newNetwork(order [6]int) *network {
+	n := &network{order: order, names: make(map[truthTable]string)}
+	for i, p := range proj {
+		n.names[p] = fmt.Sprintf("a%d", i+1)
+	}
+	return n
+}
+
+func (n *network) emit(f truthTable, gates int, format string, args ...interface{}) string {
+	n.temps++
+	name := fmt.Sprintf("x%d", n.temps)
+	fmt.Fprintf(&n.body, "\t%s := "+format+"\n", append([]interface{}{name}, args...)...)
+	n.names[f] = name
+	n.gates += gates
+	return name
+}
+
+// synth returns the name of a value computing the non-constant
+// function f, emitting gates as needed.
+func (n *network) synth(f truthTable) string {
+	if name, ok := n.names[f]; ok {
+		return name
+	}
+	if name, ok := n.names[^f]; ok {
+		return n.emit(f, 1, "^%s", name)
+	}
+	var i int
+	for _, i = range n.order {
+		if dependsOn(f, i) {
+			break
+		}
+	}
+	v := n.synth(proj[i])
+	f0, f1 := cofactor(f, i, false), cofactor(f, i, true)
+	switch {
+	case f0 == 0 && f1 == allOnes:
+		return v
+	case f0 == allOnes && f1 == 0:
+		return n.emit(f, 1, "^%s", v)
+	case f0 == 0:
+		return n.emit(f, 1, "%s & %s", v, n.synth(f1))
+	case f1 == 0:
+		return n.emit(f, 1, "%s &^ %s", n.synth(f0), v)
+	case f0 == allOnes:
+		return n.emit(f, 2, "%s | ^%s", n.synth(f1), v)
+	case f1 == allOnes:
+		return n.emit(f, 1, "%s | %s", n.synth(f0), v)
+	case f0 == ^f1:
+		return n.emit(f, 1, "%s ^ %s", n.synth(f0), v)
+	}
+	s0, s1 := n.synth(f0), n.synth(f1)
+	return n.emit(f, 3, "%s ^ (%s & (%s ^ %s))", s0, v, s0, s1)
+}
+
+// outputs returns the truth tables of the four outputs of S-box s,
+// most significant first.
+func outputs(s int) (out [4]truthTable) {
+	for x := uint(0); x < 64; x++ {
+		row := x>>4&2 | x&1
+		col := x >> 1 & 0xf
+		y := sBoxes[s][row][col]
+		for j := range out {
+			out[j] |= truthTable(y>>(3-uint(j))&1) << x
+		}
+	}
+	return out
+}
+
+func permutations(a []int, k int, f func()) {
+	if k == len(a) {
+		f()
+		return
+	}
+	for i := k; i < len(a); i++ {
+		a[k], a[i] = a[i], a[k]
+		permutations(a, k+1, f)
+		a[k], a[i] = a[i], a[k]
+	}
+}
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_sbox.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package des\n")
+
+	total := 0
+	for s := range sBoxes {
+		out := outputs(s)
+		var best *network
+		var bestOut [4]string
+		order := []int{0, 1, 2, 3, 4, 5}
+		permutations(order, 0, func() {
+			var o [6]int
+			copy(o[:], order)
+			n := newNetwork(o)
+			var names [4]string
+			for j, f := range out {
+				names[j] = n.synth(f)
+			}
+			if best == nil || n.gates < best.gates {
+				best, bestOut = n, names
+			}
+		})
+		total += best.gates
+
+		fmt.Fprintf(&buf, "\n// sbox%d evaluates S-box %d on 64 bitsliced lanes using %d gates.\n", s+1, s+1, best.gates)
+		buf.WriteString("// Inputs and outputs are ordered from most to least significant bit.\n")
+		fmt.Fprintf(&buf, "func sbox%d(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {\n", s+1)
+		buf.Write(best.body.Bytes())
+		fmt.Fprintf(&buf, "\treturn %s, %s, %s, %s\n}\n", bestOut[0], bestOut[1], bestOut[2], bestOut[3])
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("sbox.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d gates total", total)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+// KeySpace is an indexed set of candidate keys, in the format used by
+// des.Cipher.
+type KeySpace interface {
+	Len() uint64
+	Key(i uint64) uint64
+}
+
+// KeyRange is the keys whose permuted 56-bit values are in [Min, Max),
+// the ranges searched by Cracker.SearchKey.
+type KeyRange struct {
+	Min, Max uint64
+}
+
+// Len returns the number of keys in the range.
+func (r KeyRange) Len() uint64 {
+	if r.Max <= r.Min {
+		return 0
+	}
+	return r.Max - r.Min
+}
+
+// Key returns the i-th key of the range.
+func (r KeyRange) Key(i uint64) uint64 {
+	return permuteBlockInverse(r.Min+i, permutedChoice1[:])
+}
+
+// KeyList is an explicit list of keys.
+type KeyList []uint64
+
+// Len returns the number of keys in the list.
+func (l KeyList) Len() uint64 {
+	return uint64(len(l))
+}
+
+// Key returns the i-th key of the list.
+func (l KeyList) Key(i uint64) uint64 {
+	return l[i]
+}
+
+// CharsetKeys is the keys whose bytes are each drawn from a charset,
+// such as keys formed from ASCII passwords. Characters differing only
+// in the parity bit, which DES ignores, give the same key and are
+// counted once. The last byte varies fastest.
+type CharsetKeys struct {
+	sets [8][]byte
+}
+
+// NewCharsetKeys constructs the key space with the given charset for
+// each byte of the key.
+func NewCharsetKeys(charsets [8]string) *CharsetKeys {
+	k := new(CharsetKeys)
+	for i, charset := range charsets {
+		var seen [128]bool
+		for j := 0; j < len(charset); j++ {
+			b := charset[j] >> 1
+			if !seen[b] {
+				seen[b] = true
+				k.sets[i] = append(k.sets[i], b<<1)
+			}
+		}
+	}
+	return k
+}
+
+// Len returns the number of keys, saturating at the maximum uint64.
+func (k *CharsetKeys) Len() uint64 {
+	n := uint64(1)
+	for _, set := range k.sets {
+		m := uint64(len(set))
+		if m != 0 && n > ^uint64(0)/m {
+			return ^uint64(0)
+		}
+		n *= m
+	}
+	return n
+}
+
+// Key returns the i-th key.
+func (k *CharsetKeys) Key(i uint64) uint64 {
+	var key uint64
+	for b := 7; b >= 0; b-- {
+		set := k.sets[b]
+		key |= uint64(set[i%uint64(len(set))]) << (56 - 8*uint(b))
+		i /= uint64(len(set))
+	}
+	return key
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+// MITMOptions configures the disk-backed tables of meet-in-the-middle
+// attacks.
+type MITMOptions struct {
+	Dir        string // directory for temporary tables, defaulting to os.TempDir
+	MemEntries int    // entries sorted in memory before spilling to disk, defaulting to 1<<22
+}
+
+func (o *MITMOptions) table() (*diskTable, error) {
+	var opts MITMOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.MemEntries <= 0 {
+		opts.MemEntries = 1 << 22
+	}
+	return newDiskTable(opts.Dir, opts.MemEntries)
+}
+
+// MITM2Key performs the meet-in-the-middle attack of Merkle and Hellman
+// on two-key Triple DES in EDE mode, C = E_K1(D_K2(E_K1(P))), for K1
+// in k1s and K2 in k2s.
+//
+// The value a after the first encryption is fixed. For each K1, the
+// plaintext P = D_K1(a) is given to oracle, which returns its cipher
+// text C if known, and D_K1(C) is stored in a table. With a chosen
+// plaintext oracle, every K1 is tested; with a set of known pairs, as
+// in the attack of van Oorschot and Wiener, only the K1 whose
+// plaintexts are known, so the attack is repeated with different a.
+// Then for each K2, D_K2(a) is looked up in the table, since it equals
+// D_K1(C) for the correct keys.
+//
+// It returns the keys, as {K1, K2, K1} for NewTripleDESCipher, that
+// encrypt every verify pair.
+func MITM2Key(k1s, k2s KeySpace, a uint64, oracle func(in uint64) (out uint64, ok bool), verify []Pair, opts *MITMOptions) ([][3]uint64, error) {
+	t, err := opts.table()
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	for i := uint64(0); i < k1s.Len(); i++ {
+		k1 := k1s.Key(i)
+		c := NewCipher(k1)
+		if out, ok := oracle(c.DecryptBlock(a)); ok {
+			if err := t.add(c.DecryptBlock(out), k1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := t.finish(); err != nil {
+		return nil, err
+	}
+
+	var keys [][3]uint64
+	for i := uint64(0); i < k2s.Len(); i++ {
+		k2 := k2s.Key(i)
+		matches, err := t.lookup(NewCipher(k2).DecryptBlock(a))
+		if err != nil {
+			return nil, err
+		}
+		for _, k1 := range matches {
+			key := [3]uint64{k1, k2, k1}
+			if checkTripleDESKey(key, verify) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// MITM3Key performs a meet-in-the-middle attack on three-key Triple
+// DES in EDE mode, C = E_K3(D_K2(E_K1(P))), for K1, K2, and K3 in
+// k1s, k2s, and k3s. It stores E_K1(P) for each K1 of the pair in a
+// table and, for each K2 and K3, looks up E_K2(D_K3(C)). This takes
+// time proportional to the product of the sizes of k2s and k3s, so it
+// is only feasible for greatly reduced key spaces.
+//
+// It returns the keys that encrypt the pair and every verify pair.
+func MITM3Key(k1s, k2s, k3s KeySpace, p Pair, verify []Pair, opts *MITMOptions) ([][3]uint64, error) {
+	t, err := opts.table()
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	for i := uint64(0); i < k1s.Len(); i++ {
+		k1 := k1s.Key(i)
+		if err := t.add(NewCipher(k1).EncryptBlock(p.In), k1); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.finish(); err != nil {
+		return nil, err
+	}
+
+	k2c := make([]*Cipher, k2s.Len())
+	for j := range k2c {
+		k2c[j] = NewCipher(k2s.Key(uint64(j)))
+	}
+	var keys [][3]uint64
+	for i := uint64(0); i < k3s.Len(); i++ {
+		k3 := k3s.Key(i)
+		x := NewCipher(k3).DecryptBlock(p.Out)
+		for j, c := range k2c {
+			matches, err := t.lookup(c.EncryptBlock(x))
+			if err != nil {
+				return nil, err
+			}
+			for _, k1 := range matches {
+				key := [3]uint64{k1, k2s.Key(uint64(j)), k3}
+				if checkTripleDESKey(key, verify) {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	return keys, nil
+}
+
+func checkTripleDESKey(key [3]uint64, verify []Pair) bool {
+	c := NewTripleDESCipher(key)
+	for _, p := range verify {
+		if c.EncryptBlock(p.In) != p.Out {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import "testing"
+
+// restricted key spaces of 2^10 permuted keys around a known key
+func testKeySpace(key uint64) (KeyRange, uint64) {
+	permutedKey := permuteChoice1(key)
+	min := permutedKey &^ 0x3ff
+	return KeyRange{min, min + 0x400}, maskParity(key)
+}
+
+func TestKeyRange(t *testing.T) {
+	r, key := testKeySpace(0x0123456789abcdef)
+	found := false
+	for i := uint64(0); i < r.Len(); i++ {
+		k := r.Key(i)
+		if permuteChoice1(k) != r.Min+i {
+			t.Fatalf("key %d: %x does not permute to %x", i, k, r.Min+i)
+		}
+		found = found || k == key
+	}
+	if !found {
+		t.Errorf("key %x not in range", key)
+	}
+	if (KeyRange{5, 3}).Len() != 0 {
+		t.Error("inverted range not empty")
+	}
+}
+
+func TestMITM2Key(t *testing.T) {
+	k1s, k1 := testKeySpace(0x0123456789abcdef)
+	k2s, k2 := testKeySpace(0x23456789abcdef01)
+	c := NewTripleDESCipher([3]uint64{k1, k2, k1})
+	verify := []Pair{{0x70617373776f7264, c.EncryptBlock(0x70617373776f7264)}}
+	// small tables force spilling and merging several runs
+	opts := &MITMOptions{Dir: t.TempDir(), MemEntries: 100}
+
+	// chosen plaintext
+	oracle := func(in uint64) (uint64, bool) { return c.EncryptBlock(in), true }
+	keys, err := MITM2Key(k1s, k2s, 0x1122334455667788, oracle, verify, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != [3]uint64{k1, k2, k1} {
+		t.Errorf("chosen plaintext: result: %x want: %x", keys, [3]uint64{k1, k2, k1})
+	}
+
+	// known plaintext, with the pair for the correct a among others
+	const a = 0x0102030405060708
+	known := make(map[uint64]uint64)
+	for _, in := range []uint64{NewCipher(k1).DecryptBlock(a), 1, 2, 3} {
+		known[in] = c.EncryptBlock(in)
+	}
+	lookup := func(in uint64) (uint64, bool) {
+		out, ok := known[in]
+		return out, ok
+	}
+	keys, err = MITM2Key(k1s, k2s, a, lookup, verify, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != [3]uint64{k1, k2, k1} {
+		t.Errorf("known plaintext: result: %x want: %x", keys, [3]uint64{k1, k2, k1})
+	}
+	keys, err = MITM2Key(k1s, k2s, a+1, lookup, verify, opts)
+	if err != nil || len(keys) != 0 {
+		t.Errorf("known plaintext with wrong a: result: %x, %v", keys, err)
+	}
+}
+
+func TestMITM3Key(t *testing.T) {
+	k1s, k1 := testKeySpace(0x0123456789abcdef)
+	k2 := maskParity(0x23456789abcdef01)
+	k3 := maskParity(0x456789abcdef0123)
+	k2s := KeyList{k2 ^ 0x0200, k2, k2 ^ 0x0400}
+	k3s := KeyList{k3 ^ 0x0200, k3 ^ 0x0400, k3}
+	c := NewTripleDESCipher([3]uint64{k1, k2, k3})
+	p := Pair{0x70617373776f7264, c.EncryptBlock(0x70617373776f7264)}
+	verify := []Pair{{0x0123456789abcdef, c.EncryptBlock(0x0123456789abcdef)}}
+
+	keys, err := MITM3Key(k1s, k2s, k3s, p, verify, &MITMOptions{Dir: t.TempDir(), MemEntries: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != [3]uint64{k1, k2, k3} {
+		t.Errorf("result: %x want: %x", keys, [3]uint64{k1, k2, k3})
+	}
+}
+
+func TestDiskTable(t *testing.T) {
+	dt, err := newDiskTable(t.TempDir(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	for i := uint64(0); i < 100; i++ {
+		if err := dt.add(i*37%50, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := dt.finish(); err != nil {
+		t.Fatal(err)
+	}
+	for v := uint64(0); v < 55; v++ {
+		keys, err := dt.lookup(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 0
+		if v < 50 {
+			want = 2
+		}
+		if len(keys) != want {
+			t.Errorf("value %d: keys %v", v, keys)
+		}
+		for _, k := range keys {
+			if k*37%50 != v {
+				t.Errorf("value %d: key %d", v, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+// Pair is a known plaintext block and its cipher text.
+type Pair struct {
+	In  uint64
+	Out uint64
+}
+
+// Match is a key that encrypts the plaintext of a target pair to its
+// cipher text.
+type Match struct {
+	Key   uint64 // key in the format used by des.Cipher
+	Index int    // index of the pair given to the constructor
+}
+
+// MultiCracker checks each key against many plaintext and cipher text
+// pairs at once. Pairs that share a plaintext are encrypted once per
+// key and the result is looked up in a set of their cipher texts.
+type MultiCracker struct {
+	groups []multiGroup
+}
+
+type multiGroup struct {
+	in   uint64
+	outs map[uint64][]int // permuted cipher text to pair indices
+}
+
+// NewMultiCracker creates and returns a new MultiCracker for the given
+// pairs.
+func NewMultiCracker(pairs []Pair) *MultiCracker {
+	c := new(MultiCracker)
+	groups := make(map[uint64]int)
+	for i, p := range pairs {
+		in := permuteInitialBlock(p.In)
+		g, ok := groups[in]
+		if !ok {
+			g = len(c.groups)
+			groups[in] = g
+			c.groups = append(c.groups, multiGroup{in, make(map[uint64][]int)})
+		}
+		out := permuteInitialBlock(p.Out)
+		c.groups[g].outs[out] = append(c.groups[g].outs[out], i)
+	}
+	return c
+}
+
+// NewMultiCrackerPlaintext creates and returns a new MultiCracker for
+// cipher texts that share a common plaintext. Match indices refer to
+// outs.
+func NewMultiCrackerPlaintext(in uint64, outs []uint64) *MultiCracker {
+	pairs := make([]Pair, len(outs))
+	for i, out := range outs {
+		pairs[i] = Pair{in, out}
+	}
+	return NewMultiCracker(pairs)
+}
+
+// CheckKey checks the given permuted 56-bit key against every pair and
+// appends any matches to matches.
+func (c *MultiCracker) CheckKey(permutedKey uint64, matches []Match) []Match {
+	subkeys := permutedSubkeys(permutedKey)
+	for _, g := range c.groups {
+		if indices, ok := g.outs[encryptPermuted(g.in, &subkeys)]; ok {
+			key := permuteBlockInverse(permutedKey, permutedChoice1[:])
+			for _, i := range indices {
+				matches = append(matches, Match{key, i})
+			}
+		}
+	}
+	return matches
+}
+
+// SearchKey searches the permuted 56-bit keys in [min, max) and
+// returns every match, rather than stopping at the first.
+func (c *MultiCracker) SearchKey(min, max uint64) []Match {
+	var matches []Match
+	for i := min; i < max; i++ {
+		matches = c.CheckKey(i, matches)
+	}
+	return matches
+}
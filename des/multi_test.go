@@ -0,0 +1,74 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import "testing"
+
+func TestMultiCrackerCheckKey(t *testing.T) {
+	pairs := make([]Pair, len(encryptDESTests))
+	for i, tt := range encryptDESTests {
+		pairs[i] = Pair{tt.in, tt.out}
+	}
+	c := NewMultiCracker(pairs)
+
+	for i, tt := range encryptDESTests {
+		matches := c.CheckKey(permuteChoice1(tt.key), nil)
+		found := false
+		for _, m := range matches {
+			if m.Key != maskParity(tt.key) {
+				t.Errorf("#%d: match key: %x want %x", i, m.Key, maskParity(tt.key))
+			}
+			if maskParity(encryptDESTests[m.Index].key) != m.Key {
+				t.Errorf("#%d: key %x matched pair #%d", i, m.Key, m.Index)
+			}
+			found = found || m.Index == i
+		}
+		if !found {
+			t.Errorf("#%d: pair not matched by key %x", i, tt.key)
+		}
+	}
+}
+
+func TestMultiCrackerPlaintext(t *testing.T) {
+	// table A.2 uses a common plaintext with a different key per test
+	outs := make([]uint64, len(tableA2Tests))
+	for i, tt := range tableA2Tests {
+		outs[i] = tt.out
+	}
+	c := NewMultiCrackerPlaintext(tableA2Plaintext, outs)
+
+	for i, tt := range tableA2Tests {
+		permutedKey := permuteChoice1(tt.key[0])
+		matches := c.SearchKey(permutedKey, permutedKey+1)
+		if len(matches) != 1 {
+			t.Errorf("#%d: %d matches: %v", i, len(matches), matches)
+			continue
+		}
+		if m := matches[0]; m.Index != i || m.Key != maskParity(tt.key[0]) {
+			t.Errorf("#%d: result: %v want: {%x %d}", i, m, maskParity(tt.key[0]), i)
+		}
+	}
+}
+
+func TestMultiCrackerDuplicates(t *testing.T) {
+	tt := encryptDESTests[19]
+	c := NewMultiCracker([]Pair{{tt.in, tt.out}, {0, 0}, {tt.in, tt.out}})
+	matches := c.CheckKey(permuteChoice1(tt.key), nil)
+	if len(matches) != 2 || matches[0].Index != 0 || matches[1].Index != 2 {
+		t.Errorf("result: %v want matches for pairs 0 and 2", matches)
+	}
+}
+
+func BenchmarkMultiCrackerSearchKey(b *testing.B) {
+	outs := make([]uint64, 1000)
+	for i := range outs {
+		outs[i] = uint64(i) * 0x9e3779b97f4a7c15
+	}
+	c := NewMultiCrackerPlaintext(0x70617373776f7264, outs) // "password"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.SearchKey(0, 100000)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// RainbowTable is a time-memory trade-off table for recovering the
+// key that encrypts a fixed plaintext block. Each chain alternates
+// encrypting the plaintext with a permuted 56-bit key and reducing the
+// preoutput to the next key with a reduction function that depends on
+// the position in the chain, and only the first and last keys of each
+// chain are stored.
+type RainbowTable struct {
+	in       uint64 // permuted plaintext
+	chainLen int
+	chains   []rainbowChain // sorted by end
+}
+
+type rainbowChain struct {
+	start, end uint64 // permuted 56-bit keys
+}
+
+const keyMask = 1<<56 - 1
+
+const (
+	// maxChainLen is the longest chain read from a table, so that its
+	// length fits in an int on all platforms.
+	maxChainLen = 1<<31 - 1
+
+	// maxPrealloc is the most chains allocated up front when reading a
+	// table, since the count in the header may be corrupt.
+	maxPrealloc = 1 << 16
+)
+
+// preallocChains returns the capacity to allocate for n chains.
+func preallocChains(n uint64) int {
+	if n > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(n)
+}
+
+// rainbowReduce maps a preoutput to a permuted key for position i of a
+// chain.
+func rainbowReduce(out uint64, i int) uint64 {
+	return (out ^ uint64(i)*0x9e3779b97f4a7c15) & keyMask
+}
+
+// GenerateRainbowTable computes a table for the plaintext block with
+// chains of chainLen keys, starting at consecutive permuted keys from
+// start. Chains that merge into the same end are deduplicated, so the
+// table may hold fewer than the requested number of chains.
+func GenerateRainbowTable(in uint64, chainLen, chains int, start uint64) *RainbowTable {
+	t := &RainbowTable{in: permuteInitialBlock(in), chainLen: chainLen}
+	t.chains = make([]rainbowChain, chains)
+	workers := runtime.GOMAXPROCS(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < chains; i += workers {
+				key := (start + uint64(i)) & keyMask
+				t.chains[i] = rainbowChain{key, t.walk(key, 0, chainLen)}
+			}
+		}(w)
+	}
+	wg.Wait()
+	t.sort()
+	return t
+}
+
+// walk advances a chain from the key at position from to position to.
+func (t *RainbowTable) walk(key uint64, from, to int) uint64 {
+	for i := from; i < to; i++ {
+		key = rainbowReduce(encryptPermutedKey(t.in, key), i)
+	}
+	return key
+}
+
+// sort sorts the chains by end and removes chains with duplicate ends.
+func (t *RainbowTable) sort() {
+	sort.Slice(t.chains, func(i, j int) bool {
+		a, b := t.chains[i], t.chains[j]
+		return a.end < b.end || a.end == b.end && a.start < b.start
+	})
+	chains := t.chains[:0]
+	for i, c := range t.chains {
+		if i == 0 || c.end != t.chains[i-1].end {
+			chains = append(chains, c)
+		}
+	}
+	t.chains = chains
+}
+
+// Len returns the number of chains in the table.
+func (t *RainbowTable) Len() int {
+	return len(t.chains)
+}
+
+// ChainLen returns the number of keys in each chain.
+func (t *RainbowTable) ChainLen() int {
+	return t.chainLen
+}
+
+// Lookup searches the table for a key that encrypts the plaintext to
+// the cipher text and returns it in the format used by des.Cipher.
+func (t *RainbowTable) Lookup(out uint64) (key uint64, ok bool) {
+	out = permuteInitialBlock(out)
+	for pos := t.chainLen - 1; pos >= 0; pos-- {
+		end := t.walk(rainbowReduce(out, pos), pos+1, t.chainLen)
+		i := sort.Search(len(t.chains), func(i int) bool { return t.chains[i].end >= end })
+		for ; i < len(t.chains) && t.chains[i].end == end; i++ {
+			// the end may be a false alarm from a merge
+			k := t.walk(t.chains[i].start, 0, pos)
+			if encryptPermutedKey(t.in, k) == out {
+				return permuteBlockInverse(k, permutedChoice1[:]), true
+			}
+		}
+	}
+	return 0, false
+}
+
+var rainbowMagic = [8]byte{'D', 'E', 'S', 'R', 'B', 'T', '0', '1'}
+
+// WriteTo writes the table in a compact binary format: a header of
+// magic, permuted plaintext, chain length, and chain count, followed by
+// the 7-byte start and end keys of each chain, all little endian.
+func (t *RainbowTable) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var h [32]byte
+	copy(h[:], rainbowMagic[:])
+	binary.LittleEndian.PutUint64(h[8:], t.in)
+	binary.LittleEndian.PutUint64(h[16:], uint64(t.chainLen))
+	binary.LittleEndian.PutUint64(h[24:], uint64(len(t.chains)))
+	n, err := bw.Write(h[:])
+	written := int64(n)
+	var b [16]byte
+	for _, c := range t.chains {
+		if err != nil {
+			return written, err
+		}
+		binary.LittleEndian.PutUint64(b[:], c.start)
+		binary.LittleEndian.PutUint64(b[7:], c.end)
+		n, err = bw.Write(b[:14])
+		written += int64(n)
+	}
+	if err != nil {
+		return written, err
+	}
+	return written, bw.Flush()
+}
+
+// ReadRainbowTable reads a table written by WriteTo.
+func ReadRainbowTable(r io.Reader) (*RainbowTable, error) {
+	br := bufio.NewReader(r)
+	var h [32]byte
+	if _, err := io.ReadFull(br, h[:]); err != nil {
+		return nil, err
+	}
+	if string(h[:8]) != string(rainbowMagic[:]) {
+		return nil, errors.New("des: not a rainbow table")
+	}
+	chainLen := binary.LittleEndian.Uint64(h[16:])
+	if chainLen == 0 || chainLen > maxChainLen {
+		return nil, fmt.Errorf("des: rainbow table chain length %d out of range", chainLen)
+	}
+	t := &RainbowTable{
+		in:       binary.LittleEndian.Uint64(h[8:]),
+		chainLen: int(chainLen),
+	}
+	n := binary.LittleEndian.Uint64(h[24:])
+	t.chains = make([]rainbowChain, 0, preallocChains(n))
+	var b [16]byte
+	for i := uint64(0); i < n; i++ {
+		if _, err := io.ReadFull(br, b[:14]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("des: rainbow table chain %d: %v", i, err)
+		}
+		t.chains = append(t.chains, rainbowChain{
+			binary.LittleEndian.Uint64(b[:]) & keyMask,
+			binary.LittleEndian.Uint64(b[7:]) & keyMask,
+		})
+	}
+	return t, nil
+}
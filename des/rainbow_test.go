@@ -0,0 +1,87 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestRainbowTable(t *testing.T) {
+	const in = 0x70617373776f7264 // "password"
+	rt := GenerateRainbowTable(in, 50, 200, 0x123456789)
+	if rt.Len() == 0 || rt.Len() > 200 {
+		t.Fatalf("table has %d chains", rt.Len())
+	}
+
+	// every key in a stored chain is found
+	found := 0
+	for i, c := range rt.chains[:20] {
+		for pos := 0; pos < rt.chainLen; pos += 7 {
+			permutedKey := rt.walk(c.start, 0, pos)
+			out := NewCipher(permuteBlockInverse(permutedKey, permutedChoice1[:])).EncryptBlock(in)
+			key, ok := rt.Lookup(out)
+			if !ok {
+				t.Errorf("chain %d position %d: key not found", i, pos)
+				continue
+			}
+			if got := NewCipher(key).EncryptBlock(in); got != out {
+				t.Errorf("chain %d position %d: key %x encrypts to %x want %x", i, pos, key, got, out)
+			}
+			found++
+		}
+	}
+	if found == 0 {
+		t.Error("no keys found")
+	}
+
+	var buf bytes.Buffer
+	if _, err := rt.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := 32 + 14*rt.Len(); buf.Len() != want {
+		t.Errorf("table size: %d want %d", buf.Len(), want)
+	}
+	data := buf.Bytes()
+	rt2, err := ReadRainbowTable(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt2.in != rt.in || rt2.chainLen != rt.chainLen || len(rt2.chains) != len(rt.chains) {
+		t.Fatalf("read table differs")
+	}
+	for i := range rt.chains {
+		if rt2.chains[i] != rt.chains[i] {
+			t.Errorf("chain %d: %x want %x", i, rt2.chains[i], rt.chains[i])
+		}
+	}
+
+	if _, err := ReadRainbowTable(bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Error("truncated table read without error")
+	}
+}
+
+func TestReadRainbowTableCorrupt(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := GenerateRainbowTable(0x0123456789abcdef, 4, 8, 0).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	for _, tt := range []struct {
+		off int
+		x   uint64
+	}{
+		{16, 0},       // chain length
+		{16, 1 << 40}, // chain length
+		{24, 1 << 60}, // chain count beyond the data
+	} {
+		corrupt := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(corrupt[tt.off:], tt.x)
+		if _, err := ReadRainbowTable(bytes.NewReader(corrupt)); err == nil {
+			t.Errorf("header field %d = %d: no error", tt.off, tt.x)
+		}
+	}
+}
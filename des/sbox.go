@@ -0,0 +1,509 @@
+// Code generated by gen_sbox.go. DO NOT EDIT.
+
+package des
+
+// sbox1 evaluates S-box 1 on 64 bitsliced lanes using 144 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox1(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a2
+	x2 := x1 &^ a3
+	x3 := x2 ^ a5
+	x4 := a2 ^ a3
+	x5 := x4 ^ (a5 & (x4 ^ a2))
+	x6 := x3 ^ (a1 & (x3 ^ x5))
+	x7 := ^x3
+	x8 := ^x4
+	x9 := x1 | ^a3
+	x10 := x8 ^ (a5 & (x8 ^ x9))
+	x11 := x7 ^ (a1 & (x7 ^ x10))
+	x12 := x6 ^ (a6 & (x6 ^ x11))
+	x13 := x1 ^ (a5 & (x1 ^ x4))
+	x14 := ^a3
+	x15 := x14 ^ (a5 & (x14 ^ x1))
+	x16 := x13 ^ (a1 & (x13 ^ x15))
+	x17 := x2 ^ (a5 & (x2 ^ a2))
+	x18 := x5 ^ (a1 & (x5 ^ x17))
+	x19 := x16 ^ (a6 & (x16 ^ x18))
+	x20 := x12 ^ (a4 & (x12 ^ x19))
+	x21 := ^x5
+	x22 := x9 ^ (a5 & (x9 ^ x4))
+	x23 := x21 ^ (a1 & (x21 ^ x22))
+	x24 := a3 & x1
+	x25 := x24 ^ a5
+	x26 := x9 ^ (a5 & (x9 ^ x2))
+	x27 := x25 ^ (a1 & (x25 ^ x26))
+	x28 := x23 ^ (a6 & (x23 ^ x27))
+	x29 := x14 ^ (a5 & (x14 ^ a2))
+	x30 := a2 &^ a3
+	x31 := x8 ^ (a5 & (x8 ^ x30))
+	x32 := x29 ^ (a1 & (x29 ^ x31))
+	x33 := x26 ^ a1
+	x34 := x32 ^ (a6 & (x32 ^ x33))
+	x35 := x28 ^ (a4 & (x28 ^ x34))
+	x36 := a2 ^ (a5 & (a2 ^ a3))
+	x37 := x22 ^ (a1 & (x22 ^ x36))
+	x38 := x4 ^ (a5 & (x4 ^ x9))
+	x39 := x38 ^ (a1 & (x38 ^ x31))
+	x40 := x37 ^ (a6 & (x37 ^ x39))
+	x41 := ^x10
+	x42 := x41 ^ (a1 & (x41 ^ x13))
+	x43 := a2 ^ (a5 & (a2 ^ x9))
+	x44 := x31 ^ (a1 & (x31 ^ x43))
+	x45 := x42 ^ (a6 & (x42 ^ x44))
+	x46 := x40 ^ (a4 & (x40 ^ x45))
+	x47 := x36 ^ (a1 & (x36 ^ x7))
+	x48 := ^x22
+	x49 := x14 ^ (a5 & (x14 ^ x4))
+	x50 := x48 ^ (a1 & (x48 ^ x49))
+	x51 := x47 ^ (a6 & (x47 ^ x50))
+	x52 := x1 ^ (a5 & (x1 ^ x8))
+	x53 := x52 ^ a1
+	x54 := ^x30
+	x55 := x54 ^ (a5 & (x54 ^ x4))
+	x56 := x4 ^ (a5 & (x4 ^ a3))
+	x57 := x55 ^ (a1 & (x55 ^ x56))
+	x58 := x53 ^ (a6 & (x53 ^ x57))
+	x59 := x51 ^ (a4 & (x51 ^ x58))
+	return x20, x35, x46, x59
+}
+
+// sbox2 evaluates S-box 2 on 64 bitsliced lanes using 130 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox2(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a6
+	x2 := x1 ^ a3
+	x3 := x2 ^ a1
+	x4 := ^x2
+	x5 := x4 ^ a4
+	x6 := a6 | ^a3
+	x7 := x1 &^ a3
+	x8 := x6 ^ (a4 & (x6 ^ x7))
+	x9 := x5 ^ (a1 & (x5 ^ x8))
+	x10 := x3 ^ (a5 & (x3 ^ x9))
+	x11 := x1 | ^a3
+	x12 := x11 ^ a4
+	x13 := x12 ^ (a1 & (x12 ^ x5))
+	x14 := ^x12
+	x15 := x7 ^ a4
+	x16 := x14 ^ (a1 & (x14 ^ x15))
+	x17 := x13 ^ (a5 & (x13 ^ x16))
+	x18 := x10 ^ (a2 & (x10 ^ x17))
+	x19 := x1 | a3
+	x20 := x19 ^ a4
+	x21 := x20 ^ a1
+	x22 := ^x19
+	x23 := x22 | a4
+	x24 := x23 ^ a1
+	x25 := x21 ^ (a5 & (x21 ^ x24))
+	x26 := ^x7
+	x27 := ^x6
+	x28 := x26 ^ (a4 & (x26 ^ x27))
+	x29 := x28 ^ a1
+	x30 := x7 ^ (a4 & (x7 ^ x2))
+	x31 := a6 ^ (a4 & (a6 ^ x11))
+	x32 := x30 ^ (a1 & (x30 ^ x31))
+	x33 := x29 ^ (a5 & (x29 ^ x32))
+	x34 := x25 ^ (a2 & (x25 ^ x33))
+	x35 := x27 | ^a4
+	x36 := a3 ^ a4
+	x37 := x35 ^ (a1 & (x35 ^ x36))
+	x38 := a3 ^ (a4 & (a3 ^ x6))
+	x39 := x38 ^ (a1 & (x38 ^ x2))
+	x40 := x37 ^ (a5 & (x37 ^ x39))
+	x41 := ^x11
+	x42 := x41 ^ (a4 & (x41 ^ x2))
+	x43 := x22 ^ (a4 & (x22 ^ x26))
+	x44 := x42 ^ (a1 & (x42 ^ x43))
+	x45 := x7 ^ (a4 & (x7 ^ x4))
+	x46 := x4 ^ (a4 & (x4 ^ x1))
+	x47 := x45 ^ (a1 & (x45 ^ x46))
+	x48 := x44 ^ (a5 & (x44 ^ x47))
+	x49 := x40 ^ (a2 & (x40 ^ x48))
+	x50 := x6 ^ a4
+	x51 := a6 ^ a4
+	x52 := x50 ^ (a1 & (x50 ^ x51))
+	x53 := x11 ^ (a4 & (x11 ^ x22))
+	x54 := x53 ^ (a1 & (x53 ^ x14))
+	x55 := x52 ^ (a5 & (x52 ^ x54))
+	x56 := x15 ^ (a1 & (x15 ^ x53))
+	x57 := x2 ^ (a1 & (x2 ^ a3))
+	x58 := x56 ^ (a5 & (x56 ^ x57))
+	x59 := x55 ^ (a2 & (x55 ^ x58))
+	return x18, x34, x49, x59
+}
+
+// sbox3 evaluates S-box 3 on 64 bitsliced lanes using 130 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox3(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a3
+	x2 := ^a6
+	x3 := x1 ^ (a4 & (x1 ^ x2))
+	x4 := a6 | ^a3
+	x5 := a4 & x4
+	x6 := x3 ^ (a5 & (x3 ^ x5))
+	x7 := x2 ^ a3
+	x8 := a3 ^ (a4 & (a3 ^ x7))
+	x9 := ^x7
+	x10 := x4 ^ (a4 & (x4 ^ x9))
+	x11 := x8 ^ (a5 & (x8 ^ x10))
+	x12 := x6 ^ (a2 & (x6 ^ x11))
+	x13 := x2 ^ a4
+	x14 := a6 | a3
+	x15 := x14 ^ a4
+	x16 := x13 ^ (a5 & (x13 ^ x15))
+	x17 := x7 ^ a4
+	x18 := x17 ^ a5
+	x19 := x16 ^ (a2 & (x16 ^ x18))
+	x20 := x12 ^ (a1 & (x12 ^ x19))
+	x21 := x9 ^ (a4 & (x9 ^ a3))
+	x22 := ^x13
+	x23 := x21 ^ (a5 & (x21 ^ x22))
+	x24 := a3 & a6
+	x25 := x24 ^ (a4 & (x24 ^ x4))
+	x26 := x2 ^ (a4 & (x2 ^ x1))
+	x27 := x25 ^ (a5 & (x25 ^ x26))
+	x28 := x23 ^ (a2 & (x23 ^ x27))
+	x29 := ^x21
+	x30 := x2 ^ (a4 & (x2 ^ x24))
+	x31 := x29 ^ (a5 & (x29 ^ x30))
+	x32 := x2 | a3
+	x33 := a6 ^ (a4 & (a6 ^ x32))
+	x34 := x9 ^ (a5 & (x9 ^ x33))
+	x35 := x31 ^ (a2 & (x31 ^ x34))
+	x36 := x28 ^ (a1 & (x28 ^ x35))
+	x37 := x32 ^ (a4 & (x32 ^ a3))
+	x38 := ^x17
+	x39 := x37 ^ (a5 & (x37 ^ x38))
+	x40 := x24 ^ (a4 & (x24 ^ x1))
+	x41 := x21 ^ (a5 & (x21 ^ x40))
+	x42 := x39 ^ (a2 & (x39 ^ x41))
+	x43 := ^x4
+	x44 := x24 ^ (a4 & (x24 ^ x43))
+	x45 := ^x24
+	x46 := x45 ^ a4
+	x47 := x44 ^ (a5 & (x44 ^ x46))
+	x48 := x7 | a4
+	x49 := x48 ^ (a5 & (x48 ^ x9))
+	x50 := x47 ^ (a2 & (x47 ^ x49))
+	x51 := x42 ^ (a1 & (x42 ^ x50))
+	x52 := x22 ^ (a5 & (x22 ^ x9))
+	x53 := x52 ^ a2
+	x54 := ^x8
+	x55 := x54 ^ a5
+	x56 := x32 &^ a4
+	x57 := x56 ^ (a5 & (x56 ^ x10))
+	x58 := x55 ^ (a2 & (x55 ^ x57))
+	x59 := x53 ^ (a1 & (x53 ^ x58))
+	return x20, x36, x51, x59
+}
+
+// sbox4 evaluates S-box 4 on 64 bitsliced lanes using 90 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox4(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a1
+	x2 := x1 | ^a3
+	x3 := a1 ^ (a4 & (a1 ^ x2))
+	x4 := x1 ^ a3
+	x5 := x4 ^ (a4 & (x4 ^ a3))
+	x6 := x3 ^ (a5 & (x3 ^ x5))
+	x7 := ^x4
+	x8 := x7 ^ a4
+	x9 := a3 & x1
+	x10 := x9 ^ (a4 & (x9 ^ x7))
+	x11 := x8 ^ (a5 & (x8 ^ x10))
+	x12 := x6 ^ (a2 & (x6 ^ x11))
+	x13 := x2 ^ a4
+	x14 := x4 ^ (a5 & (x4 ^ x13))
+	x15 := a1 ^ (a4 & (a1 ^ x9))
+	x16 := x9 | a4
+	x17 := x15 ^ (a5 & (x15 ^ x16))
+	x18 := x14 ^ (a2 & (x14 ^ x17))
+	x19 := x12 ^ (a6 & (x12 ^ x18))
+	x20 := ^x12
+	x21 := x18 ^ (a6 & (x18 ^ x20))
+	x22 := ^a3
+	x23 := x22 ^ (a4 & (x22 ^ x4))
+	x24 := a1 | a3
+	x25 := x24 ^ (a4 & (x24 ^ x1))
+	x26 := x23 ^ (a5 & (x23 ^ x25))
+	x27 := a1 &^ a3
+	x28 := x7 ^ (a4 & (x7 ^ x27))
+	x29 := ^x8
+	x30 := x28 ^ (a5 & (x28 ^ x29))
+	x31 := x26 ^ (a2 & (x26 ^ x30))
+	x32 := x24 ^ a4
+	x33 := x32 ^ (a5 & (x32 ^ x7))
+	x34 := ^x27
+	x35 := a4 & x34
+	x36 := x34 ^ (a4 & (x34 ^ a1))
+	x37 := x35 ^ (a5 & (x35 ^ x36))
+	x38 := x33 ^ (a2 & (x33 ^ x37))
+	x39 := x31 ^ (a6 & (x31 ^ x38))
+	x40 := ^x38
+	x41 := x40 ^ (a6 & (x40 ^ x31))
+	return x19, x21, x39, x41
+}
+
+// sbox5 evaluates S-box 5 on 64 bitsliced lanes using 147 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox5(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a1
+	x2 := a5 & x1
+	x3 := x2 ^ a2
+	x4 := a1 | a5
+	x5 := x4 ^ a2
+	x6 := x3 ^ (a3 & (x3 ^ x5))
+	x7 := a5 & a1
+	x8 := x7 | ^a2
+	x9 := a1 ^ a5
+	x10 := x7 ^ (a2 & (x7 ^ x9))
+	x11 := x8 ^ (a3 & (x8 ^ x10))
+	x12 := x6 ^ (a6 & (x6 ^ x11))
+	x13 := ^x9
+	x14 := x1 | a5
+	x15 := x13 ^ (a2 & (x13 ^ x14))
+	x16 := x10 ^ (a3 & (x10 ^ x15))
+	x17 := x9 ^ (a2 & (x9 ^ x14))
+	x18 := ^x4
+	x19 := x13 ^ (a2 & (x13 ^ x18))
+	x20 := x17 ^ (a3 & (x17 ^ x19))
+	x21 := x16 ^ (a6 & (x16 ^ x20))
+	x22 := x12 ^ (a4 & (x12 ^ x21))
+	x23 := ^a5
+	x24 := x13 ^ (a2 & (x13 ^ x23))
+	x25 := x9 ^ (a3 & (x9 ^ x24))
+	x26 := ^x2
+	x27 := x18 ^ (a2 & (x18 ^ x26))
+	x28 := x14 ^ (a2 & (x14 ^ x7))
+	x29 := x27 ^ (a3 & (x27 ^ x28))
+	x30 := x25 ^ (a6 & (x25 ^ x29))
+	x31 := ^x5
+	x32 := x9 ^ a2
+	x33 := x31 ^ (a3 & (x31 ^ x32))
+	x34 := x33 ^ a6
+	x35 := x30 ^ (a4 & (x30 ^ x34))
+	x36 := ^x17
+	x37 := ^x7
+	x38 := x37 ^ (a2 & (x37 ^ a1))
+	x39 := x36 ^ (a3 & (x36 ^ x38))
+	x40 := a5 ^ a2
+	x41 := x38 ^ (a3 & (x38 ^ x40))
+	x42 := x39 ^ (a6 & (x39 ^ x41))
+	x43 := ^x38
+	x44 := ^x10
+	x45 := x43 ^ (a3 & (x43 ^ x44))
+	x46 := x13 ^ (a2 & (x13 ^ x1))
+	x47 := ^x14
+	x48 := x47 ^ (a2 & (x47 ^ a5))
+	x49 := x46 ^ (a3 & (x46 ^ x48))
+	x50 := x45 ^ (a6 & (x45 ^ x49))
+	x51 := x42 ^ (a4 & (x42 ^ x50))
+	x52 := a2 & x4
+	x53 := x52 ^ (a3 & (x52 ^ x13))
+	x54 := x9 ^ (a2 & (x9 ^ x1))
+	x55 := x32 ^ (a3 & (x32 ^ x54))
+	x56 := x53 ^ (a6 & (x53 ^ x55))
+	x57 := x4 ^ (a2 & (x4 ^ x14))
+	x58 := x23 ^ (a2 & (x23 ^ x2))
+	x59 := x57 ^ (a3 & (x57 ^ x58))
+	x60 := x7 ^ (a2 & (x7 ^ x13))
+	x61 := x14 ^ (a2 & (x14 ^ a1))
+	x62 := x60 ^ (a3 & (x60 ^ x61))
+	x63 := x59 ^ (a6 & (x59 ^ x62))
+	x64 := x56 ^ (a4 & (x56 ^ x63))
+	return x22, x35, x51, x64
+}
+
+// sbox6 evaluates S-box 6 on 64 bitsliced lanes using 138 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox6(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a2
+	x2 := x1 ^ a6
+	x3 := x1 ^ (a1 & (x1 ^ x2))
+	x4 := x1 &^ a6
+	x5 := x2 ^ (a1 & (x2 ^ x4))
+	x6 := x3 ^ (a4 & (x3 ^ x5))
+	x7 := ^x2
+	x8 := x7 ^ a1
+	x9 := x8 ^ a4
+	x10 := x6 ^ (a5 & (x6 ^ x9))
+	x11 := ^a6
+	x12 := a6 & x1
+	x13 := x11 ^ (a1 & (x11 ^ x12))
+	x14 := x12 | a1
+	x15 := x13 ^ (a4 & (x13 ^ x14))
+	x16 := a6 ^ a1
+	x17 := ^x12
+	x18 := x17 ^ (a1 & (x17 ^ a6))
+	x19 := x16 ^ (a4 & (x16 ^ x18))
+	x20 := x15 ^ (a5 & (x15 ^ x19))
+	x21 := x10 ^ (a3 & (x10 ^ x20))
+	x22 := ^x8
+	x23 := x22 ^ (a4 & (x22 ^ x16))
+	x24 := x1 | a6
+	x25 := x17 ^ (a1 & (x17 ^ x24))
+	x26 := x8 ^ (a4 & (x8 ^ x25))
+	x27 := x23 ^ (a5 & (x23 ^ x26))
+	x28 := a6 & a2
+	x29 := x7 ^ (a1 & (x7 ^ x28))
+	x30 := x11 ^ (a1 & (x11 ^ x1))
+	x31 := x29 ^ (a4 & (x29 ^ x30))
+	x32 := x12 ^ (a1 & (x12 ^ a2))
+	x33 := x7 ^ (a4 & (x7 ^ x32))
+	x34 := x31 ^ (a5 & (x31 ^ x33))
+	x35 := x27 ^ (a3 & (x27 ^ x34))
+	x36 := ^x30
+	x37 := x36 ^ a4
+	x38 := x12 ^ (a1 & (x12 ^ x24))
+	x39 := x24 ^ (a1 & (x24 ^ a2))
+	x40 := x38 ^ (a4 & (x38 ^ x39))
+	x41 := x37 ^ (a5 & (x37 ^ x40))
+	x42 := ^x14
+	x43 := ^x24
+	x44 := ^x28
+	x45 := x43 ^ (a1 & (x43 ^ x44))
+	x46 := x42 ^ (a4 & (x42 ^ x45))
+	x47 := x9 ^ (a5 & (x9 ^ x46))
+	x48 := x41 ^ (a3 & (x41 ^ x47))
+	x49 := a1 & x17
+	x50 := a2 ^ (a1 & (a2 ^ x2))
+	x51 := x49 ^ (a4 & (x49 ^ x50))
+	x52 := ^x49
+	x53 := x4 ^ (a1 & (x4 ^ x2))
+	x54 := x52 ^ (a4 & (x52 ^ x53))
+	x55 := x51 ^ (a5 & (x51 ^ x54))
+	x56 := ^x50
+	x57 := ^x53
+	x58 := x56 ^ (a4 & (x56 ^ x57))
+	x59 := ^x3
+	x60 := x59 ^ (a4 & (x59 ^ x8))
+	x61 := x58 ^ (a5 & (x58 ^ x60))
+	x62 := x55 ^ (a3 & (x55 ^ x61))
+	return x21, x35, x48, x62
+}
+
+// sbox7 evaluates S-box 7 on 64 bitsliced lanes using 125 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox7(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := a4 & a2
+	x2 := x1 ^ a5
+	x3 := ^a2
+	x4 := a2 ^ a4
+	x5 := x3 ^ (a5 & (x3 ^ x4))
+	x6 := x2 ^ (a3 & (x2 ^ x5))
+	x7 := a2 | a4
+	x8 := x4 ^ (a5 & (x4 ^ x7))
+	x9 := ^x4
+	x10 := a4 & x3
+	x11 := x9 ^ (a5 & (x9 ^ x10))
+	x12 := x8 ^ (a3 & (x8 ^ x11))
+	x13 := x6 ^ (a1 & (x6 ^ x12))
+	x14 := ^x2
+	x15 := x14 ^ a3
+	x16 := x3 | a4
+	x17 := x4 ^ (a5 & (x4 ^ x16))
+	x18 := x4 ^ (a5 & (x4 ^ x1))
+	x19 := x17 ^ (a3 & (x17 ^ x18))
+	x20 := x15 ^ (a1 & (x15 ^ x19))
+	x21 := x13 ^ (a6 & (x13 ^ x20))
+	x22 := ^x7
+	x23 := x22 ^ a5
+	x24 := ^x10
+	x25 := x24 ^ a5
+	x26 := x23 ^ (a3 & (x23 ^ x25))
+	x27 := x26 ^ (a1 & (x26 ^ x6))
+	x28 := x24 ^ (a5 & (x24 ^ a4))
+	x29 := x22 ^ (a5 & (x22 ^ a2))
+	x30 := x28 ^ (a3 & (x28 ^ x29))
+	x31 := x3 ^ a5
+	x32 := ^x16
+	x33 := x32 ^ a5
+	x34 := x31 ^ (a3 & (x31 ^ x33))
+	x35 := x30 ^ (a1 & (x30 ^ x34))
+	x36 := x27 ^ (a6 & (x27 ^ x35))
+	x37 := x17 ^ a3
+	x38 := x7 ^ (a5 & (x7 ^ x32))
+	x39 := x10 ^ (a5 & (x10 ^ x16))
+	x40 := x38 ^ (a3 & (x38 ^ x39))
+	x41 := x37 ^ (a1 & (x37 ^ x40))
+	x42 := x32 ^ (a5 & (x32 ^ x7))
+	x43 := x4 ^ (a3 & (x4 ^ x42))
+	x44 := x22 ^ (a5 & (x22 ^ x9))
+	x45 := x44 ^ a3
+	x46 := x43 ^ (a1 & (x43 ^ x45))
+	x47 := x41 ^ (a6 & (x41 ^ x46))
+	x48 := ^x5
+	x49 := ^a4
+	x50 := x49 ^ a5
+	x51 := x48 ^ (a3 & (x48 ^ x50))
+	x52 := x51 ^ a1
+	x53 := x16 ^ (a5 & (x16 ^ x4))
+	x54 := ^x28
+	x55 := x53 ^ (a3 & (x53 ^ x54))
+	x56 := ^x11
+	x57 := x56 ^ a3
+	x58 := x55 ^ (a1 & (x55 ^ x57))
+	x59 := x52 ^ (a6 & (x52 ^ x58))
+	return x21, x36, x47, x59
+}
+
+// sbox8 evaluates S-box 8 on 64 bitsliced lanes using 122 gates.
+// Inputs and outputs are ordered from most to least significant bit.
+func sbox8(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64) {
+	x1 := ^a2
+	x2 := x1 | ^a4
+	x3 := x2 ^ (a3 & (x2 ^ a4))
+	x4 := ^x2
+	x5 := x4 ^ a3
+	x6 := x3 ^ (a1 & (x3 ^ x5))
+	x7 := x1 &^ a4
+	x8 := a2 ^ (a3 & (a2 ^ x7))
+	x9 := x1 ^ a4
+	x10 := x8 ^ (a1 & (x8 ^ x9))
+	x11 := x6 ^ (a5 & (x6 ^ x10))
+	x12 := ^x9
+	x13 := x12 ^ a3
+	x14 := ^x7
+	x15 := a4 & x1
+	x16 := x14 ^ (a3 & (x14 ^ x15))
+	x17 := x13 ^ (a1 & (x13 ^ x16))
+	x18 := x1 | a4
+	x19 := x18 ^ a3
+	x20 := x19 ^ a1
+	x21 := x17 ^ (a5 & (x17 ^ x20))
+	x22 := x11 ^ (a6 & (x11 ^ x21))
+	x23 := ^x16
+	x24 := ^x8
+	x25 := x23 ^ (a1 & (x23 ^ x24))
+	x26 := a4 ^ (a3 & (a4 ^ x9))
+	x27 := x26 ^ (a1 & (x26 ^ x8))
+	x28 := x25 ^ (a5 & (x25 ^ x27))
+	x29 := x16 ^ (a1 & (x16 ^ x13))
+	x30 := ^x26
+	x31 := x30 ^ (a1 & (x30 ^ x12))
+	x32 := x29 ^ (a5 & (x29 ^ x31))
+	x33 := x28 ^ (a6 & (x28 ^ x32))
+	x34 := a2 ^ a3
+	x35 := ^x13
+	x36 := x34 ^ (a1 & (x34 ^ x35))
+	x37 := x9 ^ (a1 & (x9 ^ x30))
+	x38 := x36 ^ (a5 & (x36 ^ x37))
+	x39 := x4 ^ (a3 & (x4 ^ x1))
+	x40 := x39 ^ a1
+	x41 := ^x15
+	x42 := x9 ^ (a3 & (x9 ^ x41))
+	x43 := x15 ^ (a3 & (x15 ^ x9))
+	x44 := x42 ^ (a1 & (x42 ^ x43))
+	x45 := x40 ^ (a5 & (x40 ^ x44))
+	x46 := x38 ^ (a6 & (x38 ^ x45))
+	x47 := ^x21
+	x48 := x1 ^ (a3 & (x1 ^ a4))
+	x49 := ^x18
+	x50 := a2 ^ (a3 & (a2 ^ x49))
+	x51 := x48 ^ (a1 & (x48 ^ x50))
+	x52 := x41 ^ (a3 & (x41 ^ x7))
+	x53 := x52 ^ (a1 & (x52 ^ x24))
+	x54 := x51 ^ (a5 & (x51 ^ x53))
+	x55 := x47 ^ (a6 & (x47 ^ x54))
+	return x22, x33, x46, x55
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+// TripleDESCracker searches for Triple DES keys in EDE mode. Like
+// Cracker, it applies the initial permutation to the plaintext and
+// cipher text once, rather than for each key. Searches are over
+// constrained key spaces, such as two keys fixed and one drawn from a
+// small set, and share work between keys with a common prefix.
+type TripleDESCracker struct {
+	in  uint64
+	out uint64
+}
+
+// NewTripleDESCracker creates and returns a new TripleDESCracker.
+func NewTripleDESCracker(in, out uint64) *TripleDESCracker {
+	c := new(TripleDESCracker)
+	c.in = permuteInitialBlock(in)
+	c.out = permuteInitialBlock(out)
+	return c
+}
+
+// CheckKey checks whether the given keys, in the format used by
+// NewTripleDESCipher, encrypt to the cipher text.
+func (c *TripleDESCracker) CheckKey(key [3]uint64) bool {
+	_, ok := c.SearchKey(KeyList{key[0]}, KeyList{key[1]}, KeyList{key[2]})
+	return ok
+}
+
+// maxCachedSubkeys bounds the key spaces whose subkeys are computed
+// once rather than for each use.
+const maxCachedSubkeys = 1 << 16
+
+// SearchKey searches every combination of K1, K2, and K3 from the key
+// spaces and returns the first keys that encrypt to the cipher text.
+// The state after the first and second encryptions is reused across
+// all K2 and K3, so the cost is dominated by the K3 encryptions.
+func (c *TripleDESCracker) SearchKey(k1s, k2s, k3s KeySpace) (key [3]uint64, ok bool) {
+	k2Subkeys := cacheSubkeys(k2s)
+	k3Subkeys := cacheSubkeys(k3s)
+
+	left, right := uint32(c.in>>32), uint32(c.in)
+	left = (left << 1) | (left >> 31)
+	right = (right << 1) | (right >> 31)
+
+	var s1, s2, s3 Cipher
+	for i := uint64(0); i < k1s.Len(); i++ {
+		s1.generateSubkeys(k1s.Key(i))
+		l1, r1 := left, right
+		for n := 0; n < 8; n++ {
+			l1, r1 = feistel(l1, r1, s1.subkeys[2*n], s1.subkeys[2*n+1])
+		}
+
+		for j := uint64(0); j < k2s.Len(); j++ {
+			subkeys2 := subkeysAt(k2s, k2Subkeys, j, &s2)
+			l2, r2 := l1, r1
+			for n := 0; n < 8; n++ {
+				r2, l2 = feistel(r2, l2, subkeys2[15-2*n], subkeys2[15-(2*n+1)])
+			}
+
+			for k := uint64(0); k < k3s.Len(); k++ {
+				subkeys3 := subkeysAt(k3s, k3Subkeys, k, &s3)
+				l3, r3 := l2, r2
+				for n := 0; n < 8; n++ {
+					l3, r3 = feistel(l3, r3, subkeys3[2*n], subkeys3[2*n+1])
+				}
+				l3 = (l3 << 31) | (l3 >> 1)
+				r3 = (r3 << 31) | (r3 >> 1)
+				if uint64(r3)<<32|uint64(l3) == c.out {
+					return [3]uint64{k1s.Key(i), k2s.Key(j), k3s.Key(k)}, true
+				}
+			}
+		}
+	}
+	return [3]uint64{}, false
+}
+
+// cacheSubkeys computes the subkeys of each key in a small key space.
+func cacheSubkeys(keys KeySpace) [][16]uint64 {
+	if keys.Len() > maxCachedSubkeys {
+		return nil
+	}
+	subkeys := make([][16]uint64, keys.Len())
+	var c Cipher
+	for i := range subkeys {
+		c.generateSubkeys(keys.Key(uint64(i)))
+		subkeys[i] = c.subkeys
+	}
+	return subkeys
+}
+
+// subkeysAt returns the cached subkeys of the i-th key or generates
+// them into c.
+func subkeysAt(keys KeySpace, cache [][16]uint64, i uint64, c *Cipher) *[16]uint64 {
+	if cache != nil {
+		return &cache[i]
+	}
+	c.generateSubkeys(keys.Key(i))
+	return &c.subkeys
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Andrew Archibald. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package des
+
+import "testing"
+
+func TestTripleDESCrackerCheckKey(t *testing.T) {
+	var tests []TripleDESTest
+	tests = append(tests, encryptTripleDESTests...)
+	tests = append(tests, tableA4Tests...)
+	for i, tt := range tests {
+		c := NewTripleDESCracker(tt.in, tt.out)
+		if !c.CheckKey(tt.key) {
+			t.Errorf("#%d: key %x not ok", i, tt.key)
+		}
+		bad := [3]uint64{tt.key[0], tt.key[1], tt.key[2] ^ 0x0200}
+		if c.CheckKey(bad) {
+			t.Errorf("#%d: key should not match: %x", i, bad)
+		}
+	}
+}
+
+func TestTripleDESCrackerSearchKey(t *testing.T) {
+	tt := encryptTripleDESTests[4] // "abcdefgh12345678ABCDEFGH"
+	c := NewTripleDESCracker(tt.in, tt.out)
+
+	// K1 fixed, K2 from a small set, and K3 from ASCII charsets
+	k1s := KeyList{tt.key[0]}
+	k2s := KeyList{0x3132333435363700, 0x3132333435363738, 0x3132333435363800}
+	k3s := NewCharsetKeys([8]string{"A", "AB", "C", "Dd", "EF", "0123456789F", "GHI", "H"})
+	if n := k3s.Len(); n != 1*2*1*2*2*6*2*1 {
+		t.Errorf("charset key space has %d keys", n)
+	}
+	key, ok := c.SearchKey(k1s, k2s, k3s)
+	if !ok {
+		t.Fatal("key not found")
+	}
+	if out := NewTripleDESCipher(key).EncryptBlock(tt.in); out != tt.out {
+		t.Errorf("key %x encrypts to %x want %x", key, out, tt.out)
+	}
+	if maskParity(key[2]) != maskParity(tt.key[2]) {
+		t.Errorf("K3: %x want %x", key[2], maskParity(tt.key[2]))
+	}
+
+	if _, ok := c.SearchKey(k1s, k2s[:1], k3s); ok {
+		t.Error("key found without the correct K2")
+	}
+}
+
+func TestCharsetKeys(t *testing.T) {
+	k := NewCharsetKeys([8]string{"ab", "c", "d", "e", "f", "g", "h", "0123"})
+	// 0 and 1, and 2 and 3, differ only in parity, but a and b do not
+	if k.Len() != 2*2 {
+		t.Fatalf("Len: %d", k.Len())
+	}
+	want := []uint64{0x6062646466666830, 0x6062646466666832, 0x6262646466666830}
+	for i, w := range want {
+		if key := k.Key(uint64(i)); key != w {
+			t.Errorf("Key(%d): %x want %x", i, key, w)
+		}
+	}
+	// no two characters in a position differ only in parity
+	if n := NewCharsetKeys([8]string{"ab", "ace", "c", "d", "e", "f", "g", "0246"}).Len(); n != 2*3*4 {
+		t.Errorf("distinct charsets: Len %d", n)
+	}
+	if n := NewCharsetKeys([8]string{}).Len(); n != 0 {
+		t.Errorf("empty charsets: Len %d", n)
+	}
+}
@@ -0,0 +1,28 @@
+// Package block provides 64-bit block helpers shared by the commands.
+package block
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Password is the plaintext block "password", the most common first
+// block of passwords in the dump.
+const Password uint64 = 0x70617373776f7264
+
+// Hex is a flag.Value for a 64-bit block in hex.
+type Hex uint64
+
+func (b *Hex) String() string {
+	return fmt.Sprintf("%016x", uint64(*b))
+}
+
+// Set parses s as a block in hex.
+func (b *Hex) Set(s string) error {
+	x, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return err
+	}
+	*b = Hex(x)
+	return nil
+}
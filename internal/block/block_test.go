@@ -0,0 +1,32 @@
+package block
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestPassword(t *testing.T) {
+	if want := binary.BigEndian.Uint64([]byte("password")); Password != want {
+		t.Errorf("Password: %x want %x", Password, want)
+	}
+}
+
+func TestHex(t *testing.T) {
+	var b Hex
+	if err := b.Set("2fca9b003de39778"); err != nil {
+		t.Fatal(err)
+	}
+	if b != 0x2fca9b003de39778 {
+		t.Errorf("Set: %x", uint64(b))
+	}
+	if s := b.String(); s != "2fca9b003de39778" {
+		t.Errorf("String: %s", s)
+	}
+	b = 0xff
+	if s := b.String(); s != "00000000000000ff" {
+		t.Errorf("String: %s", s)
+	}
+	if err := b.Set("not hex"); err == nil {
+		t.Error("Set succeeded on invalid hex")
+	}
+}
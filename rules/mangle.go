@@ -0,0 +1,62 @@
+package rules
+
+// Built-in rule sets for common mangling. Each includes the identity
+// rule, so that combining sets with Product also yields the words
+// mangled by only some of them.
+var (
+	// Case gives the word in lowercase, uppercase, and capitalized.
+	Case = mustParseAll(":", "l", "u", "c")
+
+	// Leet substitutes digits or symbols for the letters a, e, i, o,
+	// s, and t in either case.
+	Leet = mustParseAll(":",
+		"sa4 sA4 se3 sE3 si1 sI1 so0 sO0 ss5 sS5 st7 sT7",
+		"sa@ sA@ se3 sE3 si! sI! so0 sO0 ss$ sS$ st+ sT+")
+
+	// Digits appends 0 through 9 and 00 through 99.
+	Digits = digits()
+)
+
+func mustParseAll(texts ...string) Rules {
+	rs := make(Rules, len(texts))
+	for i, text := range texts {
+		rs[i] = MustParse(text)
+	}
+	return rs
+}
+
+func digits() Rules {
+	texts := []string{":"}
+	for d := '0'; d <= '9'; d++ {
+		texts = append(texts, "$"+string(d))
+	}
+	for d := '0'; d <= '9'; d++ {
+		for e := '0'; e <= '9'; e++ {
+			texts = append(texts, "$"+string(d)+" $"+string(e))
+		}
+	}
+	return mustParseAll(texts...)
+}
+
+// Product returns the rules formed by applying a rule from each set in
+// turn, for every combination of rules. An empty set leaves the
+// combinations unchanged.
+func Product(sets ...Rules) Rules {
+	product := Rules{{text: ":"}}
+	for _, set := range sets {
+		if len(set) == 0 {
+			continue
+		}
+		next := make(Rules, 0, len(product)*len(set))
+		for _, a := range product {
+			for _, b := range set {
+				next = append(next, Rule{
+					text: a.text + " " + b.text,
+					ops:  append(append([]op(nil), a.ops...), b.ops...),
+				})
+			}
+		}
+		product = next
+	}
+	return product
+}
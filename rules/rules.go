@@ -0,0 +1,366 @@
+// Package rules implements word mangling rules in the syntax of
+// hashcat and John the Ripper.
+//
+// A rule is a sequence of functions applied in order to a word. Each
+// function is a character, optionally followed by positions, which are
+// 0-9 then A-Z for 10-35, or literal characters:
+//
+//	:    do nothing
+//	l    lowercase all letters
+//	u    uppercase all letters
+//	c    capitalize the first letter and lowercase the rest
+//	C    lowercase the first letter and uppercase the rest
+//	t    toggle the case of all letters
+//	TN   toggle the case of the letter at N
+//	E    title case, capitalizing letters after spaces
+//	r    reverse
+//	d    duplicate
+//	pN   append N copies of the word
+//	f    append the reversed word
+//	{    rotate left
+//	}    rotate right
+//	$X   append X
+//	^X   prepend X
+//	[    delete the first character
+//	]    delete the last character
+//	DN   delete the character at N
+//	xNM  extract M characters starting at N
+//	ONM  omit M characters starting at N
+//	iNX  insert X at N
+//	oNX  overwrite the character at N with X
+//	'N   truncate to N characters
+//	sXY  replace all X with Y
+//	@X   remove all X
+//	zN   prepend N copies of the first character
+//	ZN   append N copies of the last character
+//	q    duplicate every character
+//
+// Spaces between functions are ignored. Functions referring to
+// positions beyond the end of the word leave it unchanged.
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Rule is a parsed sequence of rule functions.
+type Rule struct {
+	text string
+	ops  []op
+}
+
+type op struct {
+	fn   byte
+	a, b byte
+}
+
+// arity gives the number of arguments of each function and whether
+// they are positions (p) or characters (c).
+var arity = map[byte]string{
+	':': "", 'l': "", 'u': "", 'c': "", 'C': "", 't': "", 'T': "p",
+	'E': "", 'r': "", 'd': "", 'p': "p", 'f': "", '{': "", '}': "",
+	'$': "c", '^': "c", '[': "", ']': "", 'D': "p", 'x': "pp",
+	'O': "pp", 'i': "pc", 'o': "pc", '\'': "p", 's': "cc", '@': "c",
+	'z': "p", 'Z': "p", 'q': "",
+}
+
+// Parse parses a rule.
+func Parse(text string) (Rule, error) {
+	r := Rule{text: text}
+	for i := 0; i < len(text); {
+		fn := text[i]
+		i++
+		if fn == ' ' || fn == '\t' {
+			continue
+		}
+		args, ok := arity[fn]
+		if !ok {
+			return Rule{}, fmt.Errorf("rules: %q: unknown function %q", text, fn)
+		}
+		o := op{fn: fn}
+		for j := 0; j < len(args); j++ {
+			if i == len(text) {
+				return Rule{}, fmt.Errorf("rules: %q: missing argument to %q", text, fn)
+			}
+			arg := text[i]
+			i++
+			if args[j] == 'p' {
+				n, ok := position(arg)
+				if !ok {
+					return Rule{}, fmt.Errorf("rules: %q: invalid position %q", text, arg)
+				}
+				arg = n
+			}
+			if j == 0 {
+				o.a = arg
+			} else {
+				o.b = arg
+			}
+		}
+		r.ops = append(r.ops, o)
+	}
+	return r, nil
+}
+
+func position(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'A' <= c && c <= 'Z':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// MustParse is like Parse, but panics if the rule cannot be parsed.
+func MustParse(text string) Rule {
+	r, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Rule) String() string {
+	return r.text
+}
+
+// Rules is a list of rules, each producing one candidate per word.
+type Rules []Rule
+
+// ReadRules parses a rule file with one rule per line. Blank lines and
+// lines beginning with # are skipped.
+func ReadRules(r io.Reader) (Rules, error) {
+	var rs Rules
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimRight(s.Text(), "\r")
+		if strings.TrimSpace(text) == "" || text[0] == '#' {
+			continue
+		}
+		rule, err := Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		rs = append(rs, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// Apply applies the rule to a word, appending the result to dst.
+func (r Rule) Apply(dst, word []byte) []byte {
+	start := len(dst)
+	w := append(dst, word...)
+	var tmp []byte
+	for _, o := range r.ops {
+		b := w[start:]
+		n := len(b)
+		switch o.fn {
+		case ':':
+		case 'l':
+			for i := range b {
+				b[i] = toLower(b[i])
+			}
+		case 'u':
+			for i := range b {
+				b[i] = toUpper(b[i])
+			}
+		case 'c', 'C':
+			for i := range b {
+				if (i == 0) == (o.fn == 'c') {
+					b[i] = toUpper(b[i])
+				} else {
+					b[i] = toLower(b[i])
+				}
+			}
+		case 't':
+			for i := range b {
+				b[i] = toggle(b[i])
+			}
+		case 'T':
+			if int(o.a) < n {
+				b[o.a] = toggle(b[o.a])
+			}
+		case 'E':
+			for i := range b {
+				if i == 0 || b[i-1] == ' ' {
+					b[i] = toUpper(b[i])
+				} else {
+					b[i] = toLower(b[i])
+				}
+			}
+		case 'r':
+			for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+		case 'd':
+			w = append(w, b...)
+		case 'p':
+			for i := 0; i < int(o.a); i++ {
+				w = append(w, w[start:start+n]...)
+			}
+		case 'f':
+			for i := n - 1; i >= 0; i-- {
+				w = append(w, w[start+i])
+			}
+		case '{':
+			if n > 0 {
+				c := b[0]
+				copy(b, b[1:])
+				b[n-1] = c
+			}
+		case '}':
+			if n > 0 {
+				c := b[n-1]
+				copy(b[1:], b)
+				b[0] = c
+			}
+		case '$':
+			w = append(w, o.a)
+		case '^':
+			w = append(w, 0)
+			copy(w[start+1:], w[start:])
+			w[start] = o.a
+		case '[':
+			if n > 0 {
+				w = append(w[:start], b[1:]...)
+			}
+		case ']':
+			if n > 0 {
+				w = w[:len(w)-1]
+			}
+		case 'D':
+			if int(o.a) < n {
+				w = append(w[:start+int(o.a)], b[o.a+1:]...)
+			}
+		case 'x':
+			if int(o.a)+int(o.b) <= n {
+				w = append(w[:start], b[o.a:o.a+o.b]...)
+			}
+		case 'O':
+			if int(o.a)+int(o.b) <= n {
+				w = append(w[:start+int(o.a)], b[o.a+o.b:]...)
+			}
+		case 'i':
+			if int(o.a) <= n {
+				w = append(w, 0)
+				copy(w[start+int(o.a)+1:], w[start+int(o.a):])
+				w[start+int(o.a)] = o.b
+			}
+		case 'o':
+			if int(o.a) < n {
+				b[o.a] = o.b
+			}
+		case '\'':
+			if int(o.a) < n {
+				w = w[:start+int(o.a)]
+			}
+		case 's':
+			for i := range b {
+				if b[i] == o.a {
+					b[i] = o.b
+				}
+			}
+		case '@':
+			j := start
+			for _, c := range b {
+				if c != o.a {
+					w[j] = c
+					j++
+				}
+			}
+			w = w[:j]
+		case 'z':
+			if n > 0 {
+				tmp = append(tmp[:0], b...)
+				w = w[:start]
+				for i := 0; i < int(o.a); i++ {
+					w = append(w, tmp[0])
+				}
+				w = append(w, tmp...)
+			}
+		case 'Z':
+			if n > 0 {
+				for i := 0; i < int(o.a); i++ {
+					w = append(w, b[n-1])
+				}
+			}
+		case 'q':
+			tmp = append(tmp[:0], b...)
+			w = w[:start]
+			for _, c := range tmp {
+				w = append(w, c, c)
+			}
+		}
+	}
+	return w
+}
+
+// Apply returns an iterator over the results of applying each rule to
+// a word. An empty list of rules yields the word unchanged.
+func (rs Rules) Apply(word []byte) *Iter {
+	return &Iter{rules: rs, word: word}
+}
+
+// Iter streams the candidates produced by rules for a word, in the
+// style of bufio.Scanner.
+type Iter struct {
+	rules Rules
+	word  []byte
+	buf   []byte
+	i     int
+}
+
+// Next advances to the next candidate and returns false when there are
+// no more.
+func (it *Iter) Next() bool {
+	if len(it.rules) == 0 {
+		if it.i != 0 {
+			return false
+		}
+		it.i++
+		it.buf = append(it.buf[:0], it.word...)
+		return true
+	}
+	if it.i == len(it.rules) {
+		return false
+	}
+	it.buf = it.rules[it.i].Apply(it.buf[:0], it.word)
+	it.i++
+	return true
+}
+
+// Word returns the current candidate. The slice is overwritten by the
+// next call to Next.
+func (it *Iter) Word() []byte {
+	return it.buf
+}
+
+func toLower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func toUpper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+func toggle(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return toLower(c)
+}
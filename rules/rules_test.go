@@ -0,0 +1,134 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+// Mostly examples from the hashcat rule documentation
+var applyTests = []struct {
+	rule, word, want string
+}{
+	{":", "p@ssW0rd", "p@ssW0rd"},
+	{"l", "p@ssW0rd", "p@ssw0rd"},
+	{"u", "p@ssW0rd", "P@SSW0RD"},
+	{"c", "p@ssW0rd", "P@ssw0rd"},
+	{"C", "p@ssW0rd", "p@SSW0RD"},
+	{"t", "p@ssW0rd", "P@SSw0RD"},
+	{"T3", "p@ssW0rd", "p@sSW0rd"},
+	{"E", "p@ssW0rd w0rld", "P@ssw0rd W0rld"},
+	{"r", "p@ssW0rd", "dr0Wss@p"},
+	{"d", "p@ssW0rd", "p@ssW0rdp@ssW0rd"},
+	{"p2", "p@ssW0rd", "p@ssW0rdp@ssW0rdp@ssW0rd"},
+	{"f", "p@ssW0rd", "p@ssW0rddr0Wss@p"},
+	{"{", "p@ssW0rd", "@ssW0rdp"},
+	{"}", "p@ssW0rd", "dp@ssW0r"},
+	{"$1", "p@ssW0rd", "p@ssW0rd1"},
+	{"^1", "p@ssW0rd", "1p@ssW0rd"},
+	{"[", "p@ssW0rd", "@ssW0rd"},
+	{"]", "p@ssW0rd", "p@ssW0r"},
+	{"D3", "p@ssW0rd", "p@sW0rd"},
+	{"x04", "p@ssW0rd", "p@ss"},
+	{"O12", "p@ssW0rd", "psW0rd"},
+	{"i4!", "p@ssW0rd", "p@ss!W0rd"},
+	{"o3$", "p@ssW0rd", "p@s$W0rd"},
+	{"'6", "p@ssW0rd", "p@ssW0"},
+	{"ss$", "p@ssW0rd", "p@$$W0rd"},
+	{"@s", "p@ssW0rd", "p@W0rd"},
+	{"z2", "p@ssW0rd", "ppp@ssW0rd"},
+	{"Z2", "p@ssW0rd", "p@ssW0rddd"},
+	{"q", "p@ssW0rd", "pp@@ssssWW00rrdd"},
+
+	{"c $1 $2 $3", "password", "Password123"},
+	{"sa@ so0 ss$", "password", "p@$$w0rd"},
+	{"^r ^u ^o ^y", "password", "yourpassword"},
+	{"TA", "password", "password"},
+	{"DA", "password", "password"},
+	{"x59", "password", "password"},
+	{"[ ] {", "", ""},
+}
+
+func TestApply(t *testing.T) {
+	for _, tt := range applyTests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Errorf("%q: %v", tt.rule, err)
+			continue
+		}
+		if got := string(r.Apply([]byte("prefix"), []byte(tt.word))); got != "prefix"+tt.want {
+			t.Errorf("%q(%q): result: %q want: %q", tt.rule, tt.word, strings.TrimPrefix(got, "prefix"), tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, rule := range []string{"$", "T", "Ta", "x1", "?", "s1"} {
+		if _, err := Parse(rule); err == nil {
+			t.Errorf("%q: no error", rule)
+		}
+	}
+}
+
+func TestIter(t *testing.T) {
+	rs, err := ReadRules(strings.NewReader("# comment\n:\n\nu\r\nc $1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for it := rs.Apply([]byte("word")); it.Next(); {
+		got = append(got, string(it.Word()))
+	}
+	if want := "word WORD Word1"; strings.Join(got, " ") != want {
+		t.Errorf("result: %q want: %q", got, want)
+	}
+
+	got = nil
+	for it := Rules(nil).Apply([]byte("word")); it.Next(); {
+		got = append(got, string(it.Word()))
+	}
+	if len(got) != 1 || got[0] != "word" {
+		t.Errorf("no rules: %q", got)
+	}
+
+	if _, err := ReadRules(strings.NewReader(":\n$")); err == nil || !strings.HasPrefix(err.Error(), "line 2") {
+		t.Errorf("error: %v", err)
+	}
+}
+
+func TestProduct(t *testing.T) {
+	tests := []struct {
+		sets []Rules
+		want []string
+	}{
+		{nil, []string{"Pass"}},
+		{[]Rules{Case}, []string{"pass", "PASS", "Pass"}},
+		{[]Rules{Leet}, []string{"P455", "P@$$"}},
+		{[]Rules{Digits}, []string{"Pass0", "Pass9", "Pass00", "Pass42", "Pass99"}},
+		{[]Rules{Case, Leet, Digits}, []string{"p455", "P455", "p@$$1", "P45512"}},
+		{[]Rules{{MustParse("r")}, nil, Case}, []string{"ssaP", "SSAP", "Ssap"}},
+	}
+	for _, tt := range tests {
+		rs := Product(tt.sets...)
+		words := make(map[string]bool)
+		for it := rs.Apply([]byte("Pass")); it.Next(); {
+			words[string(it.Word())] = true
+		}
+		n := 1
+		for _, set := range tt.sets {
+			if len(set) != 0 {
+				n *= len(set)
+			}
+		}
+		if len(rs) != n {
+			t.Errorf("%d rules want %d", len(rs), n)
+		}
+		for _, w := range tt.want {
+			if !words[w] {
+				t.Errorf("%v: missing %q", rs, w)
+			}
+		}
+	}
+	if len(Product(Case, Leet, Digits)) != 4*3*111 {
+		t.Errorf("%d rules", len(Product(Case, Leet, Digits)))
+	}
+}